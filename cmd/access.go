@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"charm.land/huh/v2"
+	"github.com/wim-vdw/wimkube/internal"
+)
+
+// Accesses needed by the actions of the menus and commands.
+var (
+	accessExec            = internal.Access{Verb: "create", Resource: "pods", Subresource: "exec"}
+	accessAttach          = internal.Access{Verb: "create", Resource: "pods", Subresource: "attach"}
+	accessDebug           = internal.Access{Verb: "patch", Resource: "pods", Subresource: "ephemeralcontainers"}
+	accessCreatePod       = internal.Access{Verb: "create", Resource: "pods"}
+	accessEvict           = internal.Access{Verb: "create", Resource: "pods", Subresource: "eviction"}
+	accessPatchNode       = internal.Access{Verb: "patch", Resource: "nodes"}
+	accessCreateJob       = internal.Access{Verb: "create", Group: "batch", Resource: "jobs"}
+	accessPatchCronJob    = internal.Access{Verb: "patch", Group: "batch", Resource: "cronjobs"}
+	accessPatchConfigMap  = internal.Access{Verb: "patch", Resource: "configmaps"}
+	accessGetSecret       = internal.Access{Verb: "get", Resource: "secrets"}
+	accessCreateNamespace = internal.Access{Verb: "create", Resource: "namespaces"}
+	accessDeleteNamespace = internal.Access{Verb: "delete", Resource: "namespaces"}
+	accessPatchSecret     = internal.Access{Verb: "patch", Resource: "secrets"}
+)
+
+// menuAction is an option of a menu. The option is only offered if the user has the access it needs.
+type menuAction struct {
+	title  string
+	value  string
+	access *internal.Access
+}
+
+// accessKey identifies a permission check of a client.
+type accessKey struct {
+	client *internal.Client
+	access internal.Access
+}
+
+var (
+	permissionsMu sync.Mutex
+	// permissions caches the permission checks of this process, so menus that are shown again do not check again.
+	permissions = map[accessKey]bool{}
+)
+
+// menuOptions returns the options of the actions in a menu that the user may perform in the namespace.
+// The permissions are checked concurrently. Actions whose permission cannot be checked are offered, so a cluster
+// that does not support access reviews keeps working as before.
+func menuOptions(ctx context.Context, c *internal.Client, namespace string, actions []menuAction) []huh.Option[string] {
+	allowed := make([]bool, len(actions))
+	var wg sync.WaitGroup
+	for i, action := range actions {
+		if action.access == nil {
+			allowed[i] = true
+			continue
+		}
+		wg.Go(func() {
+			allowed[i] = canI(ctx, c, namespace, *action.access)
+		})
+	}
+	wg.Wait()
+
+	options := make([]huh.Option[string], 0, len(actions))
+	for i, action := range actions {
+		if allowed[i] {
+			options = append(options, huh.NewOption(action.title, action.value))
+		}
+	}
+
+	return options
+}
+
+// canI reports whether the user may perform the access in the namespace. Cluster-scoped accesses ignore the
+// namespace. It reports true if the permission cannot be checked.
+func canI(ctx context.Context, c *internal.Client, namespace string, access internal.Access) bool {
+	if access.Resource != "nodes" && access.Resource != "namespaces" {
+		access.Namespace = namespace
+	}
+	key := accessKey{client: c, access: access}
+	permissionsMu.Lock()
+	allowed, checked := permissions[key]
+	permissionsMu.Unlock()
+	if checked {
+		return allowed
+	}
+
+	allowed, _, err := c.CanI(ctx, access)
+	if err != nil {
+		// Remember the failed check as allowed too, so an unreachable cluster does not slow down every menu.
+		allowed = true
+	}
+	permissionsMu.Lock()
+	permissions[key] = allowed
+	permissionsMu.Unlock()
+
+	return allowed
+}
+
+// checkAccess checks before an action that the user may perform the accesses it needs in the namespace, so the user
+// does not find out halfway through the action. It returns an error naming the first missing permission.
+func checkAccess(ctx context.Context, c *internal.Client, contextName, namespace string, accesses ...internal.Access) error {
+	for _, access := range accesses {
+		if canI(ctx, c, namespace, access) {
+			continue
+		}
+		where := fmt.Sprintf("namespace %s", namespace)
+		switch {
+		case access.Resource == "nodes" || access.Resource == "namespaces":
+			where = "the cluster"
+		case namespace == "":
+			where = "all namespaces"
+		}
+		return fmt.Errorf("you are not allowed to %s in %s of context %s (see wimkube auth can-i --list)", access, where, contextName)
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// allow makes the access reviews of the clientset allow only the accesses, given as "verb resource[/subresource]".
+func allow(clientset *fake.Clientset, accesses ...string) {
+	clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		attributes := review.Spec.ResourceAttributes
+		resource := attributes.Resource
+		if attributes.Subresource != "" {
+			resource += "/" + attributes.Subresource
+		}
+		review.Status.Allowed = slices.Contains(accesses, attributes.Verb+" "+resource)
+		return true, review, nil
+	})
+}
+
+func TestMenuOptions(t *testing.T) {
+	env := newTestEnv(t, nil)
+	allow(env.clientsets["dev"], "create pods/exec")
+	c, err := newClient("dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := menuOptions(t.Context(), c, "default", []menuAction{
+		{"List all pods", "1", nil},
+		{"Execute an interactive shell in a container of a pod", "3", &accessExec},
+		{"Debug a pod with an ephemeral container", "5", &accessDebug},
+	})
+	var got []string
+	for _, option := range options {
+		got = append(got, option.Value)
+	}
+	if want := []string{"1", "3"}; !slices.Equal(got, want) {
+		t.Errorf("menuOptions() = %v, want %v", got, want)
+	}
+}
+
+func TestCheckAccess(t *testing.T) {
+	env := newTestEnv(t, nil)
+	allow(env.clientsets["dev"], "patch nodes")
+	c, err := newClient("dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := checkAccess(t.Context(), c, "dev", "", accessPatchNode); err != nil {
+		t.Errorf("checkAccess(patch nodes) error = %v, want nil", err)
+	}
+	err = checkAccess(t.Context(), c, "dev", "", accessPatchNode, accessEvict)
+	if err == nil || !strings.Contains(err.Error(), "create pods/eviction in all namespaces") {
+		t.Errorf("checkAccess(patch nodes, create pods/eviction) error = %v, want the eviction to be refused", err)
+	}
+}
+
+func TestAuthCanI(t *testing.T) {
+	env := newTestEnv(t, nil)
+	env.clientsets["dev"].Resources = []*metav1.APIResourceList{{
+		GroupVersion: "v1",
+		APIResources: []metav1.APIResource{
+			{Name: "pods", Kind: "Pod", Namespaced: true, ShortNames: []string{"po"}},
+			{Name: "pods/exec", Kind: "PodExecOptions", Namespaced: true},
+		},
+	}}
+	allow(env.clientsets["dev"], "get pods")
+
+	tests := []struct {
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{args: []string{"get", "po"}, want: "yes\n"},
+		{args: []string{"create", "pods/exec"}, want: "no\n"},
+		{args: []string{"get", "widgets"}, wantErr: true},
+		{args: []string{"get"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			got, err := env.run(t, append([]string{"auth", "can-i"}, tt.args...)...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("auth can-i error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("auth can-i = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,356 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wim-vdw/wimkube/internal"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// crdEstablishTimeout is how long apply waits until the server serves a kind defined by a CustomResourceDefinition
+// it applied.
+const crdEstablishTimeout = 30 * time.Second
+
+// Values of the --dry-run flag of apply.
+const (
+	dryRunNone   = "none"
+	dryRunServer = "server"
+)
+
+var (
+	applyFilename       string
+	applyDryRun         string
+	applyForceConflicts bool
+	applyYes            bool
+)
+
+var editCmd = &cobra.Command{
+	Use:   "edit <kind>/<name>",
+	Short: "Edit a resource of any kind in $EDITOR and update it, like kubectl edit.",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE:  execEdit,
+}
+
+var applyCmd = &cobra.Command{
+	Use:   "apply -f <file|dir>",
+	Short: "Apply manifests with server-side apply after showing a diff.",
+	Args:  cobra.NoArgs,
+	RunE:  execApply,
+}
+
+// appliedObject is an object of a manifest together with its resource type and the diff applying it makes.
+type appliedObject struct {
+	resource internal.APIResource
+	object   *unstructured.Unstructured
+	created  bool
+	// pending is set if the kind of the object is defined by a CustomResourceDefinition of the manifests, so the resource
+	// type is resolved again once that is applied.
+	pending bool
+	diff    string
+}
+
+func execEdit(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	c, currentContext, namespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	kind, name, err := resourceArgs(ctx, c, args)
+	if err != nil {
+		return err
+	}
+	resource, err := c.FindResource(ctx, kind)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		name, err = pickObject(ctx, c, resource, namespace)
+		if err != nil {
+			return err
+		}
+	}
+	if !resource.Namespaced {
+		namespace = ""
+	}
+	objects, err := c.GetObjects(ctx, resource, namespace, name, "")
+	if err != nil {
+		return err
+	}
+	live := &objects[0]
+	original, err := internal.ObjectYAML(live, true)
+	if err != nil {
+		return err
+	}
+	liveYAML, err := internal.ObjectYAML(live, false)
+	if err != nil {
+		return err
+	}
+
+	// Reopen the editor with the changes until the server accepts them, so a typo does not lose the changes.
+	edited := original
+	var object, preview *unstructured.Unstructured
+	for {
+		edited, err = editInEditor(name+".yaml", edited)
+		if err != nil {
+			return err
+		}
+		if edited == original {
+			fmt.Println("Edit cancelled, no changes made.")
+			return nil
+		}
+		object, err = parseEdited(live, edited)
+		if err == nil {
+			preview, err = c.UpdateObject(ctx, resource, object, true)
+		}
+		if internal.IsConflict(err) {
+			return fmt.Errorf("%s was changed while it was edited, edit it again: %w", resource.ObjectName(name), err)
+		}
+		if err == nil {
+			break
+		}
+		fmt.Printf("Invalid changes: %v\n", err)
+		again, err := confirm("Edit again?")
+		if err != nil {
+			return err
+		}
+		if !again {
+			return errAborted
+		}
+	}
+	previewYAML, err := internal.ObjectYAML(preview, false)
+	if err != nil {
+		return err
+	}
+	if previewYAML == liveYAML {
+		// E.g. a removed field the server sets to its default again.
+		fmt.Printf("The server keeps %s as it is, the changes have no effect.\n", resource.ObjectName(name))
+		return nil
+	}
+	apply, err := confirmChanges(resource.ObjectName(name), liveYAML, previewYAML)
+	if err != nil || !apply {
+		return err
+	}
+	if err := confirmProtectedContext(currentContext, "edit "+resource.ObjectName(name)); err != nil {
+		return err
+	}
+	if _, err := c.UpdateObject(ctx, resource, object, false); err != nil {
+		return err
+	}
+	fmt.Printf("%s edited.\n", resource.ObjectName(name))
+
+	return nil
+}
+
+// parseEdited parses the edited YAML of a live object. The status is not edited, so it is taken from the live object.
+// It returns an error if the YAML is invalid or is not the same object anymore.
+func parseEdited(live *unstructured.Unstructured, edited string) (*unstructured.Unstructured, error) {
+	objects, err := internal.ParseManifests("the edited object", []byte(edited))
+	if err != nil {
+		return nil, err
+	}
+	if len(objects) != 1 {
+		return nil, fmt.Errorf("the edited YAML must contain exactly one object, found %d", len(objects))
+	}
+	object := objects[0]
+	if object.GroupVersionKind() != live.GroupVersionKind() || object.GetName() != live.GetName() || object.GetNamespace() != live.GetNamespace() {
+		return nil, fmt.Errorf("the apiVersion, kind, name and namespace of %s %s cannot be changed", live.GetKind(), live.GetName())
+	}
+	if status, found := live.Object["status"]; found {
+		object.Object["status"] = status
+	}
+
+	return object, nil
+}
+
+func execApply(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	if applyDryRun != dryRunNone && applyDryRun != dryRunServer {
+		return fmt.Errorf("invalid value for --dry-run: %s (must be %s or %s)", applyDryRun, dryRunNone, dryRunServer)
+	}
+	c, currentContext, namespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	objects, err := internal.ReadManifests(applyFilename)
+	if err != nil {
+		return err
+	}
+	if len(objects) == 0 {
+		return fmt.Errorf("no objects found in %s", applyFilename)
+	}
+
+	// Apply every object as dry run first, so invalid manifests are reported before anything is changed and the diff
+	// shows what the server would store. Objects in a namespace, or of a kind, that the manifests create cannot be
+	// applied as dry run before the namespace or CustomResourceDefinition exists, so their diff shows them as created.
+	applied := make([]appliedObject, 0, len(objects))
+	createdNamespaces := map[string]bool{}
+	definedKinds := map[schema.GroupKind]internal.APIResource{}
+	for _, object := range objects {
+		a, err := previewApply(ctx, c, object, namespace, createdNamespaces, definedKinds)
+		if err != nil {
+			return err
+		}
+		switch {
+		case a.resource.Group == "" && a.resource.Resource == "namespaces" && a.created:
+			createdNamespaces[object.GetName()] = true
+		case a.resource.Group == "apiextensions.k8s.io" && a.resource.Resource == "customresourcedefinitions":
+			if kind, resource, ok := definedKind(object); ok {
+				definedKinds[kind] = resource
+			}
+		}
+		applied = append(applied, a)
+	}
+
+	changed := 0
+	for _, a := range applied {
+		if a.diff != "" {
+			fmt.Print(a.diff)
+			changed++
+		}
+	}
+	if applyDryRun == dryRunServer {
+		for _, a := range applied {
+			fmt.Printf("%s %s (server dry run)\n", a.resource.ObjectName(a.object.GetName()), a.result())
+		}
+		return nil
+	}
+	if changed == 0 {
+		fmt.Println("No changes to apply.")
+		return nil
+	}
+	if !applyYes {
+		confirmed, err := confirm(fmt.Sprintf("Apply these changes in context %s?", currentContext))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return errAborted
+		}
+	}
+	if err := confirmProtectedContext(currentContext, "apply "+applyFilename); err != nil {
+		return err
+	}
+	for _, a := range applied {
+		if a.pending {
+			// The kind is defined by a CustomResourceDefinition applied before, which takes a moment to be served.
+			a.resource, err = c.WaitForResourceForObject(ctx, a.object, crdEstablishTimeout)
+			if err != nil {
+				return err
+			}
+		}
+		if _, err := c.ApplyObject(ctx, a.resource, a.object, false, applyForceConflicts); err != nil {
+			return err
+		}
+		fmt.Printf("%s %s\n", a.resource.ObjectName(a.object.GetName()), a.result())
+	}
+
+	return nil
+}
+
+// previewApply applies an object of a manifest as dry run and returns the diff against the live object. Objects in a
+// namespace the manifests create, or of a kind a CustomResourceDefinition in the manifests defines, are not applied
+// as dry run, as the server would reject them, but shown as created.
+// It returns an error if the kind is unknown, the namespace does not match --namespace or the server rejects the object.
+func previewApply(ctx context.Context, c *internal.Client, object *unstructured.Unstructured, namespace string, createdNamespaces map[string]bool, definedKinds map[schema.GroupKind]internal.APIResource) (appliedObject, error) {
+	resource, err := c.ResourceForObject(ctx, object)
+	pending := false
+	if err != nil {
+		defined, found := definedKinds[object.GroupVersionKind().GroupKind()]
+		if !found {
+			return appliedObject{}, err
+		}
+		resource, pending = defined, true
+		resource.Version = object.GroupVersionKind().Version
+	}
+	switch {
+	case !resource.Namespaced:
+		object.SetNamespace("")
+	case object.GetNamespace() == "":
+		object.SetNamespace(namespace)
+	case namespaceOverride != "" && object.GetNamespace() != namespaceOverride:
+		return appliedObject{}, fmt.Errorf("the namespace %s of %s does not match --namespace %s", object.GetNamespace(), resource.ObjectName(object.GetName()), namespaceOverride)
+	}
+	name := resource.ObjectName(object.GetName())
+	if pending || createdNamespaces[object.GetNamespace()] {
+		objectYAML, err := internal.ObjectYAML(object, false)
+		if err != nil {
+			return appliedObject{}, err
+		}
+		return appliedObject{
+			resource: resource,
+			object:   object,
+			created:  true,
+			pending:  pending,
+			diff:     internal.Diff(name+" (live)", name+" (applied)", "", objectYAML),
+		}, nil
+	}
+
+	var live *unstructured.Unstructured
+	existing, err := c.GetObjects(ctx, resource, object.GetNamespace(), object.GetName(), "")
+	switch {
+	case internal.IsNotFound(err):
+	case err != nil:
+		return appliedObject{}, err
+	default:
+		live = &existing[0]
+	}
+	preview, err := c.ApplyObject(ctx, resource, object, true, applyForceConflicts)
+	if err != nil {
+		return appliedObject{}, err
+	}
+	liveYAML, err := internal.ObjectYAML(live, false)
+	if err != nil {
+		return appliedObject{}, err
+	}
+	previewYAML, err := internal.ObjectYAML(preview, false)
+	if err != nil {
+		return appliedObject{}, err
+	}
+
+	return appliedObject{
+		resource: resource,
+		object:   object,
+		created:  live == nil,
+		diff:     internal.Diff(name+" (live)", name+" (applied)", liveYAML, previewYAML),
+	}, nil
+}
+
+// definedKind returns the kind a CustomResourceDefinition defines and its resource type, without a version.
+func definedKind(crd *unstructured.Unstructured) (schema.GroupKind, internal.APIResource, bool) {
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+	plural, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "plural")
+	scope, _, _ := unstructured.NestedString(crd.Object, "spec", "scope")
+	if kind == "" || plural == "" {
+		return schema.GroupKind{}, internal.APIResource{}, false
+	}
+	resource := internal.APIResource{Group: group, Resource: plural, Kind: kind, Namespaced: scope == "Namespaced"}
+
+	return schema.GroupKind{Group: group, Kind: kind}, resource, true
+}
+
+// result returns what applying the object does, the way kubectl apply reports it.
+func (a appliedObject) result() string {
+	switch {
+	case a.created:
+		return "created"
+	case a.diff != "":
+		return "configured"
+	}
+
+	return "unchanged"
+}
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+	rootCmd.AddCommand(applyCmd)
+	applyCmd.Flags().StringVarP(&applyFilename, "filename", "f", "", "Manifest file or directory of manifests to apply, - for stdin.")
+	applyCmd.Flags().StringVar(&applyDryRun, "dry-run", dryRunNone, "Only show the diff and validate the manifests on the server: none or server.")
+	applyCmd.Flags().BoolVar(&applyForceConflicts, "force-conflicts", false, "Take over fields owned by other field managers instead of failing.")
+	applyCmd.Flags().BoolVarP(&applyYes, "yes", "y", false, "Apply without asking for confirmation, e.g. in scripts.")
+	_ = applyCmd.MarkFlagRequired("filename")
+}
@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+)
+
+const testManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+spec:
+  replicas: 3
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  mode: fast
+`
+
+func TestApply(t *testing.T) {
+	env := newTestEnv(t, map[string][]runtime.Object{"dev": {
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "api"}, Spec: appsv1.DeploymentSpec{Replicas: ptr.To[int32](1)}},
+	}})
+	env.clientsets["dev"].Resources = testResources
+	// The fake dynamic client cannot apply, so the server is simulated by returning the applied object.
+	var applied []string
+	env.dynamics["dev"].PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchActionImpl)
+		object := &unstructured.Unstructured{}
+		if err := object.UnmarshalJSON(patch.GetPatch()); err != nil {
+			return true, nil, err
+		}
+		if len(patch.PatchOptions.DryRun) == 0 {
+			applied = append(applied, object.GetNamespace()+"/"+object.GetName())
+		}
+		return true, object, nil
+	})
+	manifest := filepath.Join(t.TempDir(), "app.yaml")
+	if err := os.WriteFile(manifest, []byte(testManifest), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := env.run(t, "apply", "-f", manifest, "--dry-run=server")
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	for _, want := range []string{
+		"--- deployment.apps/api (live)\n+++ deployment.apps/api (applied)\n",
+		"-  replicas: 1\n",
+		"+  replicas: 3\n",
+		"+++ configmap/settings (applied)\n",
+		"+  mode: fast\n",
+		"deployment.apps/api configured (server dry run)\nconfigmap/settings created (server dry run)\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q:\n%s", want, got)
+		}
+	}
+	if len(applied) != 0 {
+		t.Errorf("--dry-run=server applied %v", applied)
+	}
+
+	got, err = env.run(t, "apply", "-f", manifest, "--dry-run=none", "--yes")
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	if !strings.HasSuffix(got, "deployment.apps/api configured\nconfigmap/settings created\n") {
+		t.Errorf("output = %q, want the applied objects", got)
+	}
+	if want := "default/api default/settings"; strings.Join(applied, " ") != want {
+		t.Errorf("applied %v, want %s", applied, want)
+	}
+
+	if _, err := env.run(t, "apply", "-f", manifest, "--dry-run=client"); err == nil || !strings.Contains(err.Error(), "invalid value for --dry-run: client") {
+		t.Errorf("error = %v, want an invalid --dry-run", err)
+	}
+}
+
+// testBatchManifest creates a namespace with a deployment in it, and a custom resource of the kind it defines.
+const testBatchManifest = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: orders
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: orders
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: gadgets.example.org
+spec:
+  group: example.org
+  scope: Namespaced
+  names:
+    kind: Gadget
+    plural: gadgets
+---
+apiVersion: example.org/v1
+kind: Gadget
+metadata:
+  name: blue
+  namespace: orders
+`
+
+func TestApplyCreatesNamespacesAndKindsFirst(t *testing.T) {
+	env := newTestEnv(t, map[string][]runtime.Object{"dev": nil})
+	env.clientsets["dev"].Resources = append(slices.Clone(testResources),
+		&metav1.APIResourceList{GroupVersion: "apiextensions.k8s.io/v1", APIResources: []metav1.APIResource{
+			{Name: "customresourcedefinitions", SingularName: "customresourcedefinition", Kind: "CustomResourceDefinition", Verbs: []string{"get", "list", "patch"}},
+		}},
+	)
+	// The simulated server rejects objects in a namespace that does not exist, and serves the kind of a
+	// CustomResourceDefinition once it is applied.
+	namespaces := map[string]bool{"default": true, "": true}
+	var applied []string
+	env.dynamics["dev"].PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchActionImpl)
+		object := &unstructured.Unstructured{}
+		if err := object.UnmarshalJSON(patch.GetPatch()); err != nil {
+			return true, nil, err
+		}
+		if !namespaces[object.GetNamespace()] {
+			return true, nil, apierrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, object.GetNamespace())
+		}
+		if len(patch.PatchOptions.DryRun) > 0 {
+			return true, object, nil
+		}
+		applied = append(applied, object.GetKind()+"/"+object.GetName())
+		switch object.GetKind() {
+		case "Namespace":
+			namespaces[object.GetName()] = true
+		case "CustomResourceDefinition":
+			env.clientsets["dev"].Resources = append(env.clientsets["dev"].Resources, &metav1.APIResourceList{
+				GroupVersion: "example.org/v1",
+				APIResources: []metav1.APIResource{{Name: "gadgets", SingularName: "gadget", Kind: "Gadget", Namespaced: true, Verbs: []string{"get", "patch"}}},
+			})
+		}
+		return true, object, nil
+	})
+	manifest := filepath.Join(t.TempDir(), "batch.yaml")
+	if err := os.WriteFile(manifest, []byte(testBatchManifest), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := env.run(t, "apply", "-f", manifest, "--yes")
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	for _, want := range []string{
+		"+++ deployment.apps/api (applied)\n",
+		"+  namespace: orders\n",
+		"+++ gadget.example.org/blue (applied)\n",
+		"namespace/orders created\ndeployment.apps/api created\ncustomresourcedefinition.apiextensions.k8s.io/gadgets.example.org created\ngadget.example.org/blue created\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q:\n%s", want, got)
+		}
+	}
+	if want := "Namespace/orders Deployment/api CustomResourceDefinition/gadgets.example.org Gadget/blue"; strings.Join(applied, " ") != want {
+		t.Errorf("applied %v, want %s", applied, want)
+	}
+}
+
+func TestParseEdited(t *testing.T) {
+	live := &unstructured.Unstructured{}
+	live.SetAPIVersion("v1")
+	live.SetKind("ConfigMap")
+	live.SetNamespace("default")
+	live.SetName("settings")
+	live.Object["status"] = map[string]any{"phase": "Ready"}
+
+	tests := []struct {
+		edited  string
+		wantErr string
+	}{
+		{edited: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n  namespace: default\ndata:\n  mode: slow\n"},
+		{edited: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: other\n  namespace: default\n", wantErr: "cannot be changed"},
+		{edited: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n  namespace: default\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\n", wantErr: "exactly one object, found 2"},
+		{edited: "apiVersion: v1\nkind: ConfigMap\nmetadata: [", wantErr: "unable to parse"},
+	}
+	for _, tt := range tests {
+		object, err := parseEdited(live, tt.edited)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("parseEdited() error = %v", err)
+		}
+		if tt.wantErr == "" && err == nil && object.Object["status"] == nil {
+			t.Errorf("parseEdited() = %v, want the status of the live object", object.Object)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("parseEdited() error = %v, want %q", err, tt.wantErr)
+		}
+	}
+}
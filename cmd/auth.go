@@ -0,0 +1,534 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"charm.land/huh/v2"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wim-vdw/wimkube/internal"
+)
+
+const (
+	// expiryWarning is how long before credentials expire auth check starts warning about it.
+	expiryWarning = 24 * time.Hour
+	// clockSkewWarning and clockSkewError are the differences between the local clock and the clock of the API server
+	// from which auth check warns and fails, because certificates and tokens may be considered not yet valid or expired.
+	clockSkewWarning = 30 * time.Second
+	clockSkewError   = 5 * time.Minute
+)
+
+// Results of an auth check.
+const (
+	checkOK   = "OK"
+	checkWarn = "WARN"
+	checkFail = "FAIL"
+)
+
+// execPluginHints are the commands that usually fix failing exec plugins, by plugin binary.
+var execPluginHints = map[string]string{
+	"aws":                    "Log in again, e.g. with aws sso login, or check the AWS_PROFILE of the context.",
+	"aws-iam-authenticator":  "Check that your AWS credentials are valid, e.g. with aws sts get-caller-identity.",
+	"gke-gcloud-auth-plugin": "Log in again with gcloud auth login.",
+	"gcloud":                 "Log in again with gcloud auth login.",
+	"kubelogin":              "Log in again, e.g. with az login, or remove the cached tokens with kubelogin remove-tokens.",
+	"kubectl-oidc_login":     "Remove the cached tokens with kubectl oidc-login clean and log in again.",
+}
+
+// matrixVerbs are the columns of auth can-i --list.
+var matrixVerbs = []string{"get", "list", "watch", "create", "update", "patch", "delete"}
+
+// matrixResources are the rows auth can-i --list always shows, before the other resources named in the rules.
+var matrixResources = []internal.Access{
+	{Resource: "pods"},
+	{Resource: "pods", Subresource: "exec"},
+	{Resource: "pods", Subresource: "log"},
+	{Resource: "pods", Subresource: "ephemeralcontainers"},
+	{Resource: "services"},
+	{Resource: "configmaps"},
+	{Resource: "secrets"},
+	{Resource: "events"},
+	{Group: "apps", Resource: "deployments"},
+	{Group: "apps", Resource: "deployments", Subresource: "scale"},
+	{Group: "apps", Resource: "statefulsets"},
+	{Group: "apps", Resource: "daemonsets"},
+	{Group: "batch", Resource: "jobs"},
+	{Group: "batch", Resource: "cronjobs"},
+	{Resource: "nodes"},
+	{Resource: "namespaces"},
+}
+
+var (
+	authOutput        string
+	canIList          bool
+	canIAllNamespaces bool
+)
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Inspect authentication.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return loopMenu(showAuthMenu)
+	},
+}
+
+var authWhoAmICmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Display the user the cluster authenticates you as and when your credentials expire.",
+	Args:  cobra.NoArgs,
+	RunE:  execAuthWhoAmI,
+}
+
+var authCanICmd = &cobra.Command{
+	Use:   "can-i [verb] [resource] [resource-name]",
+	Short: "Check whether you are allowed to perform an action, or list what you are allowed to do.",
+	Example: `  wimkube auth can-i create pods/exec
+  wimkube auth can-i delete deploy.apps api -n payments
+  wimkube auth can-i --list`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if canIList {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.RangeArgs(2, 3)(cmd, args)
+	},
+	RunE: execAuthCanI,
+}
+
+var authCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Diagnose problems with the credentials of the current context.",
+	Args:  cobra.NoArgs,
+	RunE:  execAuthCheck,
+}
+
+// authCheck is the result of a single check of auth check.
+type authCheck struct {
+	name    string
+	result  string
+	message string
+	hint    string
+}
+
+// whoAmI is the JSON representation of auth whoami.
+type whoAmI struct {
+	User        *internal.UserInfo    `json:"user"`
+	Credentials *internal.Credentials `json:"credentials"`
+}
+
+func showAuthMenu() error {
+	var option string
+	currentContext, err := resolveContext()
+	if err != nil {
+		return err
+	}
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title(fmt.Sprintf("Select an option (context: %s)", currentContext)).
+				Options(
+					huh.NewOption("Who am I", "1"),
+					huh.NewOption("Check authentication", "2"),
+					huh.NewOption("List your permissions in the current namespace", "3"),
+				).
+				Value(&option),
+		),
+	)
+	err = runMenu(form)
+	if err != nil {
+		return err
+	}
+	switch option {
+	case "1":
+		return execAuthWhoAmI(nil, nil)
+	case "2":
+		return execAuthCheck(nil, nil)
+	case "3":
+		c, currentContext, currentNamespace, err := resolveTarget()
+		if err != nil {
+			return err
+		}
+		return listPermissions(commandContext(nil), c, currentContext, currentNamespace)
+	}
+
+	return nil
+}
+
+func execAuthWhoAmI(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	if err := checkOutput(authOutput, outputJSON); err != nil {
+		return err
+	}
+	currentContext, err := resolveContext()
+	if err != nil {
+		return err
+	}
+	creds, err := kubeConfig.GetCredentials(currentContext)
+	if err != nil {
+		return err
+	}
+	if creds.Type == internal.CredentialExecPlugin {
+		creds.Expiry, err = kubeConfig.RunExecPlugin(ctx, currentContext)
+		if err != nil {
+			return fmt.Errorf("%w (run wimkube auth check for a diagnosis)", err)
+		}
+	}
+	c, err := newClient(currentContext)
+	if err != nil {
+		return err
+	}
+	user, err := c.WhoAmI(ctx)
+	if err != nil {
+		return fmt.Errorf("%w (run wimkube auth check for a diagnosis)", err)
+	}
+	if authOutput == outputJSON {
+		return printJSON(whoAmI{User: user, Credentials: creds})
+	}
+
+	w := newTableWriter()
+	fmt.Fprintf(w, "Context:\t%s\n", currentContext)
+	fmt.Fprintf(w, "Username:\t%s\n", user.Username)
+	if user.UID != "" {
+		fmt.Fprintf(w, "UID:\t%s\n", user.UID)
+	}
+	fmt.Fprintf(w, "Groups:\t%s\n", valueOrNone(strings.Join(user.Groups, ", ")))
+	for _, key := range slices.Sorted(maps.Keys(user.Extra)) {
+		fmt.Fprintf(w, "Extra %s:\t%s\n", key, strings.Join(user.Extra[key], ", "))
+	}
+	fmt.Fprintf(w, "Credentials:\t%s\n", describeCredentials(creds, false))
+	fmt.Fprintf(w, "Expires:\t%s\n", formatExpiry(creds.Expiry))
+	if !creds.CAExpiry.IsZero() {
+		fmt.Fprintf(w, "CA expires:\t%s\n", formatExpiry(creds.CAExpiry))
+	}
+
+	return w.Flush()
+}
+
+func execAuthCanI(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	c, currentContext, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	if canIList {
+		if canIAllNamespaces {
+			return fmt.Errorf("--list cannot be combined with --all-namespaces, the rules are listed per namespace")
+		}
+		return listPermissions(ctx, c, currentContext, currentNamespace)
+	}
+
+	group, resource, subresource, err := c.ResolveResource(ctx, args[1])
+	if err != nil {
+		return err
+	}
+	access := internal.Access{Verb: args[0], Group: group, Resource: resource, Subresource: subresource, Namespace: currentNamespace}
+	if len(args) == 3 {
+		access.Name = args[2]
+	}
+	if canIAllNamespaces {
+		access.Namespace = ""
+	}
+	allowed, reason, err := c.CanI(ctx, access)
+	if err != nil {
+		return err
+	}
+	answer := "no"
+	if allowed {
+		answer = "yes"
+	}
+	if reason != "" {
+		answer += " - " + reason
+	}
+	fmt.Println(answer)
+
+	return nil
+}
+
+// listPermissions prints a matrix of the verbs the user may perform on common resources and on the resources named
+// in the rules that apply to the user in the namespace.
+func listPermissions(ctx context.Context, c *internal.Client, contextName, namespace string) error {
+	rules, incomplete, err := c.GetRules(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	rows := slices.Clone(matrixResources)
+	var extra []internal.Access
+	for _, rule := range rules {
+		for _, group := range rule.APIGroups {
+			for _, resource := range rule.Resources {
+				if group == "*" || strings.Contains(resource, "*") {
+					continue
+				}
+				name, subresource, _ := strings.Cut(resource, "/")
+				row := internal.Access{Group: group, Resource: name, Subresource: subresource}
+				if !slices.Contains(rows, row) && !slices.Contains(extra, row) {
+					extra = append(extra, row)
+				}
+			}
+		}
+	}
+	slices.SortFunc(extra, func(a, b internal.Access) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	rows = append(rows, extra...)
+
+	fmt.Printf("Permissions in namespace %s of context %s:\n", namespace, contextName)
+	w := newTableWriter()
+	fmt.Fprintf(w, "RESOURCE\t%s\n", strings.ToUpper(strings.Join(matrixVerbs, "\t")))
+	partial := false
+	for _, row := range rows {
+		resource := row.Resource
+		if row.Subresource != "" {
+			resource += "/" + row.Subresource
+		}
+		cells := make([]string, 0, len(matrixVerbs))
+		for _, verb := range matrixVerbs {
+			cell := "no"
+			for _, rule := range rules {
+				allowed, names := rule.Allows(verb, row.Group, resource)
+				if allowed && !names {
+					cell = "yes"
+					break
+				}
+				if allowed {
+					cell = "some"
+					partial = true
+				}
+			}
+			cells = append(cells, cell)
+		}
+		// The verb is empty, so the access is printed as just the resource.
+		fmt.Fprintf(w, "%s\t%s\n", strings.TrimSpace(row.String()), strings.Join(cells, "\t"))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if partial {
+		fmt.Println("\nsome: only for specific resource names.")
+	}
+	if incomplete != "" {
+		fmt.Printf("\nThe list may be incomplete: %s.\nCheck a single permission with wimkube auth can-i <verb> <resource>.\n", incomplete)
+	}
+
+	return nil
+}
+
+func execAuthCheck(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	currentContext, err := resolveContext()
+	if err != nil {
+		return err
+	}
+	checks := checkAuth(ctx, currentContext)
+
+	failed := 0
+	for _, check := range checks {
+		fmt.Printf("[%-4s] %s: %s\n", check.result, check.name, check.message)
+		if check.hint != "" {
+			fmt.Printf("       %s\n", check.hint)
+		}
+		if check.result == checkFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed for context %s", failed, len(checks), currentContext)
+	}
+
+	return nil
+}
+
+// checkAuth checks the credentials of a context in the kubeconfig and against the cluster.
+// The checks stop at the first failure that makes the next checks meaningless.
+func checkAuth(ctx context.Context, contextName string) []authCheck {
+	var checks []authCheck
+	creds, err := kubeConfig.GetCredentials(contextName)
+	if err != nil {
+		return append(checks, authCheck{"Kubeconfig", checkFail, err.Error(), "Fix the user of the context in " + kubeConfigPath() + "."})
+	}
+	checks = append(checks, checkCredentials(creds))
+	if creds.Type == internal.CredentialExecPlugin {
+		check := checkExecPlugin(ctx, contextName, creds)
+		checks = append(checks, check)
+		if check.result == checkFail {
+			return checks
+		}
+	}
+	if creds.Type != internal.CredentialExecPlugin && creds.Type != internal.CredentialNone {
+		checks = append(checks, checkExpiry(strings.ToUpper(creds.Type[:1])+creds.Type[1:], creds.Expiry, credentialsHint(creds)))
+	}
+	if !creds.CAExpiry.IsZero() {
+		checks = append(checks, checkExpiry("Cluster CA certificate", creds.CAExpiry, "Get a new kubeconfig for the cluster from its administrator or provider."))
+	}
+
+	c, err := newClient(contextName)
+	if err != nil {
+		return append(checks, authCheck{"Client", checkFail, err.Error(), "Fix the cluster and user of the context in " + kubeConfigPath() + "."})
+	}
+	check := checkClockSkew(ctx, c)
+	checks = append(checks, check)
+	if check.name == "API server" {
+		return checks
+	}
+
+	return append(checks, checkAuthentication(ctx, c))
+}
+
+// checkCredentials checks the type of the credentials of a context.
+func checkCredentials(creds *internal.Credentials) authCheck {
+	check := authCheck{name: "Credentials", result: checkOK, message: describeCredentials(creds, false)}
+	switch {
+	case creds.Type == internal.CredentialNone:
+		check.result = checkWarn
+		check.message = fmt.Sprintf("user %s has no credentials, requests are anonymous", creds.User)
+	case creds.Type == internal.CredentialAuthProvider && creds.Provider == "gcp":
+		check.result = checkFail
+		check.message += " was removed from client-go"
+		check.hint = "Install gke-gcloud-auth-plugin and run gcloud container clusters get-credentials again."
+	case creds.Type == internal.CredentialAuthProvider && creds.Provider == "azure":
+		check.result = checkFail
+		check.message += " was removed from client-go"
+		check.hint = "Install kubelogin and convert the kubeconfig with kubelogin convert-kubeconfig."
+	case creds.Type == internal.CredentialBasicAuth:
+		check.result = checkWarn
+		check.message += ", which most clusters no longer support"
+	}
+
+	return check
+}
+
+// checkExecPlugin runs the exec plugin of a context and checks the credentials it returns.
+func checkExecPlugin(ctx context.Context, contextName string, creds *internal.Credentials) authCheck {
+	expiry, err := kubeConfig.RunExecPlugin(ctx, contextName)
+	if errors.Is(err, exec.ErrNotFound) || errors.Is(err, os.ErrNotExist) {
+		hint := creds.InstallHint
+		if hint == "" {
+			hint = fmt.Sprintf("Install %s and make sure it is in your PATH.", creds.Command)
+		}
+		return authCheck{"Exec plugin", checkFail, fmt.Sprintf("%s not found in PATH", creds.Command), strings.TrimSpace(hint)}
+	}
+	if err != nil {
+		hint := execPluginHints[filepath.Base(creds.Command)]
+		if hint == "" {
+			hint = "Run the plugin yourself to see its full output: " + strings.Join(append([]string{creds.Command}, creds.Args...), " ")
+		}
+		return authCheck{"Exec plugin", checkFail, err.Error(), hint}
+	}
+	check := checkExpiry("Exec plugin credentials", expiry, execPluginHints[filepath.Base(creds.Command)])
+	if expiry.IsZero() {
+		check.message = creds.Command + " returned credentials without an expiry"
+	}
+
+	return check
+}
+
+// checkExpiry checks that credentials or a certificate are not expired and do not expire soon.
+func checkExpiry(name string, expiry time.Time, hint string) authCheck {
+	switch {
+	case expiry.IsZero():
+		return authCheck{name: name, result: checkOK, message: "no expiry"}
+	case time.Until(expiry) <= 0:
+		return authCheck{name, checkFail, formatExpiry(expiry), hint}
+	case time.Until(expiry) < expiryWarning:
+		return authCheck{name, checkWarn, formatExpiry(expiry), hint}
+	}
+
+	return authCheck{name: name, result: checkOK, message: formatExpiry(expiry)}
+}
+
+// checkClockSkew compares the local clock with the clock of the API server.
+// It returns a failed API server check if the server cannot be reached.
+func checkClockSkew(ctx context.Context, c *internal.Client) authCheck {
+	serverTime, err := c.ServerTime(ctx)
+	if err != nil {
+		return authCheck{"API server", checkFail, err.Error(), "Check the server URL of the cluster, your network connection, VPN and proxy settings."}
+	}
+	skew := time.Since(serverTime).Round(time.Second)
+	if skew < 0 {
+		skew = -skew
+	}
+	hint := "Synchronize the clock of this machine, e.g. with timedatectl set-ntp true or by enabling automatic time."
+	switch {
+	case skew >= clockSkewError:
+		return authCheck{"Clock skew", checkFail, fmt.Sprintf("the local clock differs %s from the API server", skew), hint}
+	case skew >= clockSkewWarning:
+		return authCheck{"Clock skew", checkWarn, fmt.Sprintf("the local clock differs %s from the API server", skew), hint}
+	}
+
+	return authCheck{name: "Clock skew", result: checkOK, message: "the local clock matches the API server"}
+}
+
+// checkAuthentication checks that the API server accepts the credentials.
+func checkAuthentication(ctx context.Context, c *internal.Client) authCheck {
+	user, err := c.WhoAmI(ctx)
+	switch {
+	case internal.IsUnauthorized(err):
+		return authCheck{"Authentication", checkFail, "the API server rejected the credentials", "Refresh or replace the credentials of the context; they may be revoked or issued for another cluster."}
+	case internal.IsNotFound(err):
+		return authCheck{"Authentication", checkWarn, "the API server does not support SelfSubjectReview (Kubernetes 1.28 or later)", ""}
+	case internal.IsForbidden(err):
+		return authCheck{name: "Authentication", result: checkOK, message: "authenticated, but not allowed to review the user"}
+	case err != nil:
+		return authCheck{"Authentication", checkFail, err.Error(), ""}
+	}
+
+	return authCheck{name: "Authentication", result: checkOK, message: fmt.Sprintf("authenticated as %s", user.Username)}
+}
+
+// describeCredentials returns a short description of the credentials of a context. With withArgs, the command of an
+// exec plugin includes its arguments.
+func describeCredentials(creds *internal.Credentials, withArgs bool) string {
+	description := fmt.Sprintf("%s of user %s", creds.Type, creds.User)
+	switch creds.Type {
+	case internal.CredentialExecPlugin:
+		command := creds.Command
+		if withArgs {
+			command = strings.Join(append([]string{creds.Command}, creds.Args...), " ")
+		}
+		description = fmt.Sprintf("exec plugin %s of user %s", command, creds.User)
+	case internal.CredentialAuthProvider:
+		description = fmt.Sprintf("auth provider %s of user %s", creds.Provider, creds.User)
+	}
+	if creds.Issuer != "" {
+		description += " (OIDC, issuer " + creds.Issuer + ")"
+	}
+
+	return description
+}
+
+// credentialsHint returns how to replace expired credentials of a type.
+func credentialsHint(creds *internal.Credentials) string {
+	switch creds.Type {
+	case internal.CredentialClientCertificate:
+		return "Renew the client certificate, or get a new kubeconfig for the cluster from its administrator or provider."
+	case internal.CredentialToken, internal.CredentialTokenFile:
+		return "Request a new token, e.g. with kubectl create token for a service account."
+	case internal.CredentialAuthProvider:
+		return "Log in again with the tool of the " + creds.Provider + " auth provider."
+	}
+
+	return ""
+}
+
+// kubeConfigPath returns the kubeconfig file of this invocation.
+func kubeConfigPath() string {
+	return viper.GetString("kubeconfig")
+}
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+	authCmd.AddCommand(authWhoAmICmd)
+	authCmd.AddCommand(authCheckCmd)
+	authCmd.AddCommand(authCanICmd)
+	addOutputFlag(authWhoAmICmd, &authOutput, outputJSON)
+	authCanICmd.Flags().BoolVar(&canIList, "list", false, "List what you are allowed to do in the namespace as a matrix of resources and verbs.")
+	authCanICmd.Flags().BoolVarP(&canIAllNamespaces, "all-namespaces", "A", false, "Check the permission in all namespaces instead of one namespace.")
+}
@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"fmt"
+
+	"charm.land/huh/v2"
+	"github.com/spf13/cobra"
+	"github.com/wim-vdw/wimkube/internal"
+)
+
+var configMapCmd = &cobra.Command{
+	Use:     "configmap",
+	Aliases: []string{"cm"},
+	Short:   "Manage config maps.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return loopMenu(showConfigMapMenu)
+	},
+}
+
+var configMapListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all config maps.",
+	RunE:  execConfigMapList,
+}
+
+var configMapKeysCmd = &cobra.Command{
+	Use:   "keys [configmap-name]",
+	Short: "List the keys of a config map and the size of their values.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  execConfigMapKeys,
+}
+
+var configMapGetCmd = &cobra.Command{
+	Use:   "get [configmap-name] [key]",
+	Short: "Get the value of a key of a config map.",
+	Args:  cobra.ExactArgs(2),
+	RunE:  execConfigMapGet,
+}
+
+var configMapEditCmd = &cobra.Command{
+	Use:   "edit [configmap-name] [key]",
+	Short: "Edit the value of a key of a config map in $EDITOR.",
+	Args:  cobra.ExactArgs(2),
+	RunE:  execConfigMapEdit,
+}
+
+func showConfigMapMenu() error {
+	ctx := commandContext(nil)
+	var option string
+	c, _, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("Select an option (namespace: %s)", currentNamespace)
+	options := menuOptions(ctx, c, currentNamespace, []menuAction{
+		{"List all config maps", "1", nil},
+		{"List the keys of a config map", "2", nil},
+		{"Get the value of a key of a config map", "3", nil},
+		{"Edit the value of a key of a config map", "4", &accessPatchConfigMap},
+	})
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title(title).
+				Options(options...).
+				Value(&option),
+		),
+	)
+	err = runMenu(form)
+	if err != nil {
+		return err
+	}
+	if option == "1" {
+		return execConfigMapList(nil, nil)
+	}
+
+	configMaps, err := c.GetConfigMaps(ctx, currentNamespace)
+	if err != nil {
+		return err
+	}
+	if len(configMaps) == 0 {
+		fmt.Printf("No resources found in %s namespace.\n", currentNamespace)
+		return nil
+	}
+	configMapNames := make([]string, 0, len(configMaps))
+	for _, cm := range configMaps {
+		configMapNames = append(configMapNames, cm.Name)
+	}
+	title = fmt.Sprintf("Select a config map (namespace: %s)", currentNamespace)
+	configMapName, err := pickValue(title, configMapNames, "", "")
+	if err != nil {
+		return err
+	}
+	if option == "2" {
+		return execConfigMapKeys(nil, []string{configMapName})
+	}
+
+	keys, err := c.GetConfigMapKeys(ctx, currentNamespace, configMapName)
+	if err != nil {
+		return err
+	}
+	key, err := selectDataKey(configMapName, keys)
+	if err != nil {
+		return err
+	}
+	if key == "" {
+		return nil
+	}
+	switch option {
+	case "3":
+		return execConfigMapGet(nil, []string{configMapName, key})
+	case "4":
+		return execConfigMapEdit(nil, []string{configMapName, key})
+	}
+
+	return nil
+}
+
+// selectDataKey lets the user pick one of the keys of a config map or secret.
+func selectDataKey(objectName string, keys []internal.DataKey) (string, error) {
+	if len(keys) == 0 {
+		fmt.Printf("%s has no keys.\n", objectName)
+		return "", nil
+	}
+	keyNames := make([]string, 0, len(keys))
+	for _, key := range keys {
+		keyNames = append(keyNames, key.Name)
+	}
+
+	title := fmt.Sprintf("Select a key (%s)", objectName)
+	key, err := pickValue(title, keyNames, "", "")
+	if err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
+
+// printDataKeys prints the keys of a config map or secret and the size of their values.
+func printDataKeys(keys []internal.DataKey) error {
+	w := newTableWriter()
+	fmt.Fprintln(w, "KEY\tSIZE")
+	for _, key := range keys {
+		size := fmt.Sprintf("%d bytes", key.Size)
+		if key.Binary {
+			size += " (binary)"
+		}
+		fmt.Fprintf(w, "%s\t%s\n", key.Name, size)
+	}
+
+	return w.Flush()
+}
+
+func execConfigMapList(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	c, _, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	configMaps, err := c.GetConfigMaps(ctx, currentNamespace)
+	if err != nil {
+		return err
+	}
+	if len(configMaps) == 0 {
+		fmt.Printf("No resources found in %s namespace.\n", currentNamespace)
+		return nil
+	}
+	w := newTableWriter()
+	fmt.Fprintln(w, "NAME\tKEYS\tAGE")
+	for _, cm := range configMaps {
+		fmt.Fprintf(w, "%s\t%d\t%s\n", cm.Name, cm.Keys, formatAge(cm.Created))
+	}
+
+	return w.Flush()
+}
+
+func execConfigMapKeys(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	configMapName := args[0]
+	c, _, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	keys, err := c.GetConfigMapKeys(ctx, currentNamespace, configMapName)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		fmt.Printf("Config map %s has no keys.\n", configMapName)
+		return nil
+	}
+
+	return printDataKeys(keys)
+}
+
+func execConfigMapGet(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	configMapName := args[0]
+	key := args[1]
+	c, _, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	value, _, err := c.GetConfigMapValue(ctx, currentNamespace, configMapName, key)
+	if err != nil {
+		return err
+	}
+	fmt.Println(value)
+
+	return nil
+}
+
+func execConfigMapEdit(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	configMapName := args[0]
+	key := args[1]
+	c, currentContext, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	value, resourceVersion, err := c.GetConfigMapValue(ctx, currentNamespace, configMapName, key)
+	if err != nil {
+		return err
+	}
+	edited, err := editInEditor(key, value)
+	if err != nil {
+		return err
+	}
+	apply, err := confirmChanges(configMapName+"/"+key, value, edited)
+	if err != nil || !apply {
+		return err
+	}
+	if err := confirmProtectedContext(currentContext, "edit config map "+configMapName); err != nil {
+		return err
+	}
+	err = c.SetConfigMapValue(ctx, currentNamespace, configMapName, key, edited, resourceVersion)
+	if internal.IsConflict(err) {
+		return fmt.Errorf("config map %s was changed while it was edited, edit it again: %w", configMapName, err)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Key %s of config map %s updated.\n", key, configMapName)
+
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(configMapCmd)
+	configMapCmd.AddCommand(configMapListCmd)
+	configMapCmd.AddCommand(configMapKeysCmd)
+	configMapCmd.AddCommand(configMapGetCmd)
+	configMapCmd.AddCommand(configMapEditCmd)
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"charm.land/huh/v2"
+	"github.com/spf13/viper"
+)
+
+var errAborted = errors.New("aborted by user")
+
+// isProtectedContext reports whether the context matches one of the glob patterns
+// listed under protected-contexts in the configuration file.
+func isProtectedContext(contextName string) bool {
+	for _, pattern := range viper.GetStringSlice("protected-contexts") {
+		if matched, _ := path.Match(pattern, contextName); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// confirmProtectedContext asks the user to confirm an action when the context is protected.
+// It returns errAborted if the user declines and nil if the context is not protected.
+func confirmProtectedContext(contextName, action string) error {
+	if !isProtectedContext(contextName) {
+		return nil
+	}
+	confirmed, err := confirm(fmt.Sprintf("Context %s is protected. Do you really want to %s?", contextName, action))
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return errAborted
+	}
+
+	return nil
+}
+
+// confirm shows a yes/no prompt and returns the answer of the user.
+func confirm(title string) (bool, error) {
+	var confirmed bool
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title(title).
+				Affirmative("Yes").
+				Negative("No").
+				Value(&confirmed),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return false, err
+	}
+
+	return confirmed, nil
+}
+
+// confirmName asks the user to type a name to confirm an action that cannot be undone.
+// It returns errAborted if the user types another name.
+func confirmName(title, name string) error {
+	var typed string
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title(title).
+				Description(fmt.Sprintf("Type %s to confirm.", name)).
+				Value(&typed),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return err
+	}
+	if strings.TrimSpace(typed) != name {
+		return errAborted
+	}
+
+	return nil
+}
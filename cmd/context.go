@@ -1,17 +1,28 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"charm.land/huh/v2"
 	"github.com/spf13/cobra"
+	"github.com/wim-vdw/wimkube/internal"
+)
+
+var (
+	contextExpiringWithin string
+	contextDescribeProbe  bool
 )
 
 var contextCmd = &cobra.Command{
 	Use:   "context",
 	Short: "Manage contexts.",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return showContextMenu()
+		return loopMenu(showContextMenu)
 	},
 }
 
@@ -21,6 +32,13 @@ var contextListCmd = &cobra.Command{
 	RunE:  execContextList,
 }
 
+var contextDescribeCmd = &cobra.Command{
+	Use:   "describe [context|filter]",
+	Short: "Describe the cluster and credentials of a context.",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  execContextDescribe,
+}
+
 var contextGetCmd = &cobra.Command{
 	Use:   "get",
 	Short: "Get current context.",
@@ -28,14 +46,14 @@ var contextGetCmd = &cobra.Command{
 }
 
 var contextSetCmd = &cobra.Command{
-	Use:   "set [context]",
+	Use:   "set [context|filter]",
 	Short: "Set current context.",
-	Args:  cobra.ExactArgs(1),
+	Args:  cobra.MaximumNArgs(1),
 	RunE:  execContextSet,
 }
 
 func showContextMenu() error {
-	var option, contextName string
+	var option string
 	form := huh.NewForm(
 		huh.NewGroup(
 			huh.NewSelect[string]().
@@ -44,11 +62,12 @@ func showContextMenu() error {
 					huh.NewOption("Get current context", "1"),
 					huh.NewOption("List all contexts", "2"),
 					huh.NewOption("Set current context", "3"),
+					huh.NewOption("Describe a context", "4"),
 				).
 				Value(&option),
 		),
 	)
-	err := form.Run()
+	err := runMenu(form)
 	if err != nil {
 		return err
 	}
@@ -58,22 +77,9 @@ func showContextMenu() error {
 	case "2":
 		return execContextList(nil, nil)
 	case "3":
-		contextNames := kubeConfig.GetContextNames()
-		currentContext, _ := kubeConfig.GetCurrentContext()
-		contextName = currentContext
-		form := huh.NewForm(
-			huh.NewGroup(
-				huh.NewSelect[string]().
-					Title("Select a context").
-					Options(huh.NewOptions(contextNames...)...).
-					Value(&contextName),
-			),
-		)
-		err = form.Run()
-		if err != nil {
-			return err
-		}
-		return execContextSet(nil, []string{contextName})
+		return execContextSet(nil, nil)
+	case "4":
+		return execContextDescribe(nil, nil)
 	}
 
 	return nil
@@ -85,6 +91,13 @@ func execContextList(cmd *cobra.Command, args []string) error {
 		fmt.Println("No contexts found in kubeconfig.")
 		return nil
 	}
+	if contextExpiringWithin != "" {
+		within, err := parseDuration(contextExpiringWithin)
+		if err != nil {
+			return fmt.Errorf("invalid value for --expiring-within: %s (e.g. 30d or 12h)", contextExpiringWithin)
+		}
+		return listExpiringContexts(contextNames, within)
+	}
 	for _, contextName := range contextNames {
 		fmt.Println(contextName)
 	}
@@ -92,8 +105,130 @@ func execContextList(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// listExpiringContexts prints the contexts whose credentials or cluster CA certificate in the kubeconfig expire within
+// the duration, or have already expired. Exec plugins are not run, their credentials are usually refreshed anyway.
+func listExpiringContexts(contextNames []string, within time.Duration) error {
+	deadline := time.Now().Add(within)
+	w := newTableWriter()
+	fmt.Fprintln(w, "CONTEXT\tCREDENTIALS\tEXPIRES")
+	found := false
+	for _, contextName := range contextNames {
+		creds, err := kubeConfig.GetCredentials(contextName)
+		if err != nil {
+			fmt.Fprintf(w, "%s\t<error>\t%v\n", contextName, err)
+			found = true
+			continue
+		}
+		expiring := []struct {
+			what   string
+			expiry time.Time
+		}{
+			{creds.Type, creds.Expiry},
+			{"cluster CA certificate", creds.CAExpiry},
+		}
+		for _, e := range expiring {
+			if !e.expiry.IsZero() && e.expiry.Before(deadline) {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", contextName, e.what, formatExpiry(e.expiry))
+				found = true
+			}
+		}
+	}
+	if !found {
+		fmt.Printf("No credentials or certificates expire within %s.\n", contextExpiringWithin)
+		return nil
+	}
+
+	return w.Flush()
+}
+
+func execContextDescribe(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	var contextName string
+	if len(args) == 1 {
+		contextName = args[0]
+	}
+	contextNames := kubeConfig.GetContextNames()
+	if !slices.Contains(contextNames, contextName) {
+		currentContext, _ := resolveContext()
+		var err error
+		contextName, err = pickValue("Select a context", contextNames, currentContext, contextName)
+		if err != nil {
+			return err
+		}
+	}
+	cluster, err := kubeConfig.GetClusterInfo(contextName)
+	if err != nil {
+		return err
+	}
+	creds, err := kubeConfig.GetCredentials(contextName)
+	if err != nil {
+		return err
+	}
+
+	w := newTableWriter()
+	fmt.Fprintf(w, "Name:\t%s\n", contextName)
+	fmt.Fprintf(w, "Cluster:\t%s\n", cluster.Cluster)
+	fmt.Fprintf(w, "Server:\t%s\n", cluster.Server)
+	if cluster.ProxyURL != "" {
+		fmt.Fprintf(w, "Proxy:\t%s\n", cluster.ProxyURL)
+	}
+	switch {
+	case strings.HasPrefix(cluster.Server, "http://"):
+		fmt.Fprintf(w, "CA certificate:\tnone (plain HTTP)\n")
+	case cluster.InsecureSkipTLSVerify:
+		fmt.Fprintf(w, "CA certificate:\tnot verified (insecure-skip-tls-verify)\n")
+	case cluster.CAFingerprint == "":
+		fmt.Fprintf(w, "CA certificate:\tsystem trust store\n")
+	default:
+		fmt.Fprintf(w, "CA certificate:\t%s\n", cluster.CASubject)
+		fmt.Fprintf(w, "CA fingerprint:\tSHA256 %s\n", cluster.CAFingerprint)
+		fmt.Fprintf(w, "CA expires:\t%s\n", formatExpiry(cluster.CAExpiry))
+	}
+	fmt.Fprintf(w, "Credentials:\t%s\n", describeCredentials(creds, true))
+	switch creds.Type {
+	case internal.CredentialExecPlugin:
+		fmt.Fprintf(w, "Expires:\tdecided by %s (see wimkube auth whoami)\n", creds.Command)
+	case internal.CredentialBasicAuth, internal.CredentialNone:
+	default:
+		fmt.Fprintf(w, "Expires:\t%s\n", formatExpiry(creds.Expiry))
+	}
+	fmt.Fprintf(w, "Namespace:\t%s\n", cluster.Namespace)
+	if contextDescribeProbe {
+		version, err := probeServerVersion(ctx, contextName)
+		if err != nil {
+			version = fmt.Sprintf("<unreachable> (%v)", err)
+		}
+		fmt.Fprintf(w, "Server version:\t%s\n", version)
+	}
+
+	return w.Flush()
+}
+
+// probeServerVersion asks the API server of a context for its version.
+func probeServerVersion(ctx context.Context, contextName string) (string, error) {
+	c, err := newClient(contextName)
+	if err != nil {
+		return "", err
+	}
+
+	return c.ServerVersion(ctx)
+}
+
+// parseDuration parses a duration like time.ParseDuration does, and also accepts a number of days, e.g. 30d.
+func parseDuration(s string) (time.Duration, error) {
+	if days, found := strings.CutSuffix(s, "d"); found {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	return time.ParseDuration(s)
+}
+
 func execContextGet(cmd *cobra.Command, args []string) error {
-	currentContext, err := kubeConfig.GetCurrentContext()
+	currentContext, err := resolveContext()
 	if err != nil {
 		return err
 	}
@@ -103,7 +238,20 @@ func execContextGet(cmd *cobra.Command, args []string) error {
 }
 
 func execContextSet(cmd *cobra.Command, args []string) error {
-	contextName := args[0]
+	var contextName string
+	if len(args) == 1 {
+		contextName = args[0]
+	}
+	contextNames := kubeConfig.GetContextNames()
+	if !slices.Contains(contextNames, contextName) {
+		// Not the exact name of a context, let the user pick one of the contexts matching the filter.
+		currentContext, _ := resolveContext()
+		var err error
+		contextName, err = pickValue("Select a context", contextNames, currentContext, contextName)
+		if err != nil {
+			return err
+		}
+	}
 	err := kubeConfig.SetContext(contextName)
 	if err != nil {
 		return err
@@ -118,4 +266,7 @@ func init() {
 	contextCmd.AddCommand(contextListCmd)
 	contextCmd.AddCommand(contextSetCmd)
 	contextCmd.AddCommand(contextGetCmd)
+	contextCmd.AddCommand(contextDescribeCmd)
+	contextListCmd.Flags().StringVar(&contextExpiringWithin, "expiring-within", "", "Only list contexts whose credentials or cluster CA certificate expire within this duration, e.g. 30d.")
+	contextDescribeCmd.Flags().BoolVar(&contextDescribeProbe, "probe", false, "Ask the API server for its version.")
 }
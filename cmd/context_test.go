@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "30d", want: 30 * 24 * time.Hour},
+		{value: "12h", want: 12 * time.Hour},
+		{value: "1h30m", want: 90 * time.Minute},
+		{value: "d", wantErr: true},
+		{value: "3x", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseDuration(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDuration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContextCommands(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "list",
+			args: []string{"context", "list"},
+			want: []string{"dev\nprod\n"},
+		},
+		{
+			name: "list expiring credentials",
+			args: []string{"context", "list", "--expiring-within", "30d"},
+			want: []string{"No credentials or certificates expire within 30d."},
+		},
+		{
+			name:    "list with an invalid duration",
+			args:    []string{"context", "list", "--expiring-within", "soon"},
+			wantErr: true,
+		},
+		{
+			name: "describe",
+			args: []string{"context", "describe", "prod"},
+			want: []string{"Server:           https://prod.example.com", "CA certificate:   system trust store", "Credentials:      token of user ", "Namespace:        payments"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t, nil)
+			got, err := env.run(t, tt.args...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("wimkube %s error = %v, wantErr %v", strings.Join(tt.args, " "), err, tt.wantErr)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("wimkube %s = %q, want it to contain %q", strings.Join(tt.args, " "), got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"charm.land/huh/v2"
+	"github.com/spf13/cobra"
+)
+
+var cronJobCmd = &cobra.Command{
+	Use:     "cronjob",
+	Aliases: []string{"cj"},
+	Short:   "Manage cron jobs.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return loopMenu(showCronJobMenu)
+	},
+}
+
+var cronJobListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all cron jobs.",
+	RunE:  execCronJobList,
+}
+
+var cronJobTriggerCmd = &cobra.Command{
+	Use:   "trigger [cronjob-name]",
+	Short: "Create a job from the job template of a cron job.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  execCronJobTrigger,
+}
+
+var cronJobSuspendCmd = &cobra.Command{
+	Use:   "suspend [cronjob-name]",
+	Short: "Suspend the scheduling of a cron job.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  execCronJobSuspend,
+}
+
+var cronJobResumeCmd = &cobra.Command{
+	Use:   "resume [cronjob-name]",
+	Short: "Resume the scheduling of a cron job.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  execCronJobResume,
+}
+
+func showCronJobMenu() error {
+	ctx := commandContext(nil)
+	var option string
+	c, _, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("Select an option (namespace: %s)", currentNamespace)
+	options := menuOptions(ctx, c, currentNamespace, []menuAction{
+		{"List all cron jobs", "1", nil},
+		{"Trigger a cron job", "2", &accessCreateJob},
+		{"Suspend a cron job", "3", &accessPatchCronJob},
+		{"Resume a cron job", "4", &accessPatchCronJob},
+	})
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title(title).
+				Options(options...).
+				Value(&option),
+		),
+	)
+	err = runMenu(form)
+	if err != nil {
+		return err
+	}
+	if option == "1" {
+		return execCronJobList(nil, nil)
+	}
+
+	cronJobs, err := c.GetCronJobs(ctx, currentNamespace)
+	if err != nil {
+		return err
+	}
+	if len(cronJobs) == 0 {
+		fmt.Printf("No resources found in %s namespace.\n", currentNamespace)
+		return nil
+	}
+	cronJobOptions := make([]huh.Option[string], 0, len(cronJobs))
+	for _, cj := range cronJobs {
+		label := fmt.Sprintf("%s (%s)", cj.Name, cj.Schedule)
+		if cj.Suspended {
+			label += " [suspended]"
+		}
+		cronJobOptions = append(cronJobOptions, huh.NewOption(label, cj.Name))
+	}
+	cronJobName, err := pick(fmt.Sprintf("Select a cron job (namespace: %s)", currentNamespace), cronJobOptions, "", "")
+	if err != nil {
+		return err
+	}
+	switch option {
+	case "2":
+		return execCronJobTrigger(nil, []string{cronJobName})
+	case "3":
+		return execCronJobSuspend(nil, []string{cronJobName})
+	case "4":
+		return execCronJobResume(nil, []string{cronJobName})
+	}
+
+	return nil
+}
+
+func execCronJobList(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	c, _, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	cronJobs, err := c.GetCronJobs(ctx, currentNamespace)
+	if err != nil {
+		return err
+	}
+	if len(cronJobs) == 0 {
+		fmt.Printf("No resources found in %s namespace.\n", currentNamespace)
+		return nil
+	}
+	w := newTableWriter()
+	fmt.Fprintln(w, "NAME\tSCHEDULE\tSUSPEND\tACTIVE\tLAST SCHEDULE\tAGE")
+	for _, cj := range cronJobs {
+		fmt.Fprintf(w, "%s\t%s\t%t\t%d\t%s\t%s\n", cj.Name, cj.Schedule, cj.Suspended, cj.Active, formatTimeAgo(cj.LastSchedule), formatAge(cj.Created))
+	}
+
+	return w.Flush()
+}
+
+func execCronJobTrigger(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	cronJobName := args[0]
+	c, currentContext, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	if err := confirmProtectedContext(currentContext, "trigger cron job "+cronJobName); err != nil {
+		return err
+	}
+	jobName, err := c.TriggerCronJob(ctx, currentNamespace, cronJobName)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("job/%s created from cronjob/%s\n", jobName, cronJobName)
+
+	return nil
+}
+
+func execCronJobSuspend(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	return setCronJobSuspended(ctx, args[0], true)
+}
+
+func execCronJobResume(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	return setCronJobSuspended(ctx, args[0], false)
+}
+
+func setCronJobSuspended(ctx context.Context, cronJobName string, suspended bool) error {
+	c, currentContext, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	action := "resume cron job " + cronJobName
+	if suspended {
+		action = "suspend cron job " + cronJobName
+	}
+	if err := confirmProtectedContext(currentContext, action); err != nil {
+		return err
+	}
+	err = c.SetCronJobSuspended(ctx, currentNamespace, cronJobName, suspended)
+	if err != nil {
+		return err
+	}
+	if suspended {
+		fmt.Printf("cronjob/%s suspended\n", cronJobName)
+	} else {
+		fmt.Printf("cronjob/%s resumed\n", cronJobName)
+	}
+
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(cronJobCmd)
+	cronJobCmd.AddCommand(cronJobListCmd)
+	cronJobCmd.AddCommand(cronJobTriggerCmd)
+	cronJobCmd.AddCommand(cronJobSuspendCmd)
+	cronJobCmd.AddCommand(cronJobResumeCmd)
+}
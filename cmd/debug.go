@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wim-vdw/wimkube/internal"
+)
+
+// containerStartTimeout is the time to wait for a debug or shell container to start, including pulling its image.
+const containerStartTimeout = 5 * time.Minute
+
+var (
+	debugImage  string
+	debugTarget string
+	debugCopyTo string
+)
+
+var podDebugCmd = &cobra.Command{
+	Use:   "debug [pod-name|filter]",
+	Short: "Debug a pod with an ephemeral container or in a copy of the pod.",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  execPodDebug,
+}
+
+func execPodDebug(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	c, currentContext, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	required := []internal.Access{accessDebug, accessAttach}
+	if debugCopyTo != "" {
+		required = []internal.Access{accessCreatePod, accessAttach}
+	}
+	if err := checkAccess(ctx, c, currentContext, currentNamespace, required...); err != nil {
+		return err
+	}
+	var podName string
+	if len(args) == 1 {
+		podName = args[0]
+	}
+	pods, err := c.GetPods(ctx, currentNamespace)
+	if err != nil {
+		return err
+	}
+	if len(pods) == 0 {
+		fmt.Printf("No resources found in %s namespace.\n", currentNamespace)
+		return nil
+	}
+	podName, err = selectPod(ctx, currentNamespace, pods, podName, c)
+	if err != nil {
+		return err
+	}
+
+	if debugCopyTo != "" {
+		if err := confirmProtectedContext(currentContext, fmt.Sprintf("create a debug copy %s of pod %s", debugCopyTo, podName)); err != nil {
+			return err
+		}
+		containerName, err := c.CopyPodForDebug(ctx, currentNamespace, podName, debugCopyTo, debugImage)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created pod %s, a copy of pod %s with debug container %s.\n", debugCopyTo, podName, containerName)
+		err = attachDebugContainer(ctx, c, currentNamespace, debugCopyTo, containerName)
+		if err != nil {
+			return err
+		}
+		deleteCopy, err := confirm(fmt.Sprintf("Delete pod %s?", debugCopyTo))
+		if err != nil || !deleteCopy {
+			fmt.Printf("Pod %s is kept, delete it when you are done debugging.\n", debugCopyTo)
+			return err
+		}
+		if err := c.DeletePod(ctx, currentNamespace, debugCopyTo); err != nil {
+			return err
+		}
+		fmt.Printf("Pod %s deleted.\n", debugCopyTo)
+		return nil
+	}
+
+	target := debugTarget
+	if target == "" {
+		target, err = selectContainer(ctx, currentNamespace, podName, c)
+		if err != nil {
+			return err
+		}
+	}
+	if err := confirmProtectedContext(currentContext, "add a debug container to pod "+podName); err != nil {
+		return err
+	}
+	containerName, err := c.AddDebugContainer(ctx, currentNamespace, podName, debugImage, target)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Added debug container %s to pod %s, targeting container %s.\n", containerName, podName, target)
+
+	return attachDebugContainer(ctx, c, currentNamespace, podName, containerName)
+}
+
+// attachDebugContainer waits until a debug container is running and attaches the terminal to it.
+func attachDebugContainer(ctx context.Context, c *internal.Client, namespace, podName, containerName string) error {
+	fmt.Printf("Waiting for container %s to start...\n", containerName)
+	if err := c.WaitForContainerRunning(ctx, namespace, podName, containerName, containerStartTimeout); err != nil {
+		return err
+	}
+	fmt.Println("If you don't see a command prompt, try pressing enter.")
+
+	return c.AttachToContainer(ctx, namespace, podName, containerName)
+}
+
+func init() {
+	podCmd.AddCommand(podDebugCmd)
+	podDebugCmd.Flags().StringVar(&debugImage, "image", "busybox", "Image of the debug container.")
+	podDebugCmd.Flags().StringVar(&debugTarget, "target", "", "Container whose process namespace the debug container shares. If not specified, you are asked to pick one when the pod has more than one container.")
+	podDebugCmd.Flags().StringVar(&debugCopyTo, "copy-to", "", "Create a copy of the pod with this name and a debug container instead of adding an ephemeral container.")
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/wim-vdw/wimkube/internal"
+)
+
+// editorCommand returns the editor configured through KUBE_EDITOR or EDITOR, falling back to vi (notepad on Windows).
+func editorCommand() []string {
+	for _, env := range []string{"KUBE_EDITOR", "EDITOR"} {
+		if editor := strings.Fields(os.Getenv(env)); len(editor) > 0 {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+
+	return []string{"vi"}
+}
+
+// editInEditor writes content to a temporary file, opens it in the editor of the user and returns the edited content.
+// The name is used as suffix of the temporary file so editors can apply syntax highlighting.
+func editInEditor(name, content string) (string, error) {
+	f, err := os.CreateTemp("", "wimkube-*-"+filepath.Base(name))
+	if err != nil {
+		return "", fmt.Errorf("unable to create temporary file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return "", fmt.Errorf("unable to write temporary file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("unable to write temporary file: %w", err)
+	}
+
+	editor := editorCommand()
+	c := exec.Command(editor[0], append(editor[1:], f.Name())...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return "", fmt.Errorf("unable to run editor %s: %w", editor[0], err)
+	}
+	edited, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", fmt.Errorf("unable to read temporary file: %w", err)
+	}
+	// Editors like vi end the last line with a newline. Drop it if the content had none, so saving a value without
+	// changes is not a change.
+	if !strings.HasSuffix(content, "\n") {
+		return strings.TrimSuffix(string(edited), "\n"), nil
+	}
+
+	return string(edited), nil
+}
+
+// confirmChanges shows the diff between the original and the edited content and asks the user to apply it.
+// It returns false without asking if nothing was changed.
+func confirmChanges(name, original, edited string) (bool, error) {
+	if original == edited {
+		fmt.Println("Edit cancelled, no changes made.")
+		return false, nil
+	}
+	fmt.Print(internal.Diff(name+" (live)", name+" (edited)", original, edited))
+
+	return confirm("Apply these changes?")
+}
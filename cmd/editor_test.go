@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestEditInEditor(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test editor is a shell script")
+	}
+	tests := []struct {
+		name    string
+		content string
+		saved   string
+		want    string
+	}{
+		{name: "newline added to a value without one", content: "secret", saved: "secret\n", want: "secret"},
+		{name: "changed value", content: "secret", saved: "changed\n", want: "changed"},
+		{name: "content ending with a newline", content: "a: 1\n", saved: "a: 2\n", want: "a: 2\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			saved := filepath.Join(dir, "saved")
+			if err := os.WriteFile(saved, []byte(tt.saved), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			// The editor replaces the file with what the user saves, e.g. vi adding a newline at the end.
+			editor := filepath.Join(dir, "editor")
+			if err := os.WriteFile(editor, []byte("#!/bin/sh\ncat "+saved+" > \"$1\"\n"), 0o700); err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv("KUBE_EDITOR", editor)
+
+			got, err := editInEditor("value", tt.content)
+			if err != nil {
+				t.Fatalf("editInEditor() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("editInEditor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
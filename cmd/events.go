@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wim-vdw/wimkube/internal"
+)
+
+var eventsWarningsOnly bool
+
+var eventsCmd = &cobra.Command{
+	Use:         "events",
+	Short:       "List the events in the current namespace.",
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{fanoutAnnotation: "true"},
+	RunE:        execEvents,
+}
+
+func execEvents(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	if isFanout() {
+		return execEventsFanout(ctx)
+	}
+	c, _, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	events, err := c.GetEvents(ctx, currentNamespace, eventsWarningsOnly)
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		fmt.Printf("No resources found in %s namespace.\n", currentNamespace)
+		return nil
+	}
+
+	w := newTableWriter()
+	fmt.Fprintln(w, "LAST SEEN\tTYPE\tREASON\tOBJECT\tMESSAGE")
+	for _, event := range events {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", formatAge(event.LastSeen), event.Type, event.Reason, event.Object, event.Message)
+	}
+
+	return w.Flush()
+}
+
+func execEventsFanout(ctx context.Context) error {
+	contexts, err := selectedContexts()
+	if err != nil {
+		return err
+	}
+	results := fanOut(contexts, func(contextName string) ([]internal.Event, error) {
+		c, namespace, err := contextClient(contextName)
+		if err != nil {
+			return nil, err
+		}
+		return c.GetEvents(ctx, namespace, eventsWarningsOnly)
+	})
+
+	w := newTableWriter()
+	fmt.Fprintln(w, "CONTEXT\tNAMESPACE\tLAST SEEN\tTYPE\tREASON\tOBJECT\tMESSAGE")
+	for _, result := range results {
+		for _, event := range result.Value {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", result.Context, event.Namespace, formatAge(event.LastSeen), event.Type, event.Reason, event.Object, event.Message)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	return reportFanoutErrors(results)
+}
+
+func init() {
+	rootCmd.AddCommand(eventsCmd)
+	eventsCmd.Flags().BoolVar(&eventsWarningsOnly, "warnings", false, "Only list Warning events.")
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/wim-vdw/wimkube/internal"
+)
+
+// fanoutAnnotation marks the commands that can run against several contexts at once.
+const fanoutAnnotation = "fanout"
+
+var (
+	contextPatterns []string
+	allContexts     bool
+)
+
+// fanoutResult is the result of a fan-out query against a single context.
+type fanoutResult[T any] struct {
+	Context string
+	Value   T
+	Err     error
+}
+
+// isFanout reports whether a fan-out over several contexts was requested with --all-contexts, or with --context
+// given a list or a glob pattern. A single context name only overrides the current context.
+func isFanout() bool {
+	if allContexts || len(contextPatterns) > 1 {
+		return true
+	}
+
+	return len(contextPatterns) == 1 && strings.ContainsAny(contextPatterns[0], `*?[\`)
+}
+
+// checkFanout returns an error if several contexts are selected for a command that does not support a fan-out.
+func checkFanout(cmd *cobra.Command) error {
+	if !isFanout() || cmd.Annotations[fanoutAnnotation] == "true" {
+		return nil
+	}
+
+	return fmt.Errorf("multiple contexts are not supported by %s", cmd.CommandPath())
+}
+
+// selectedContexts returns the contexts selected with --context or --all-contexts, in sorted order.
+// Patterns are glob patterns, e.g. prod-*. It returns an error if a pattern matches no context.
+func selectedContexts() ([]string, error) {
+	contextNames := kubeConfig.GetContextNames()
+	if allContexts {
+		return contextNames, nil
+	}
+	var selected []string
+	for _, pattern := range contextPatterns {
+		matched := false
+		for _, contextName := range contextNames {
+			ok, err := path.Match(pattern, contextName)
+			if err != nil {
+				return nil, fmt.Errorf("invalid context pattern %s: %w", pattern, err)
+			}
+			if ok {
+				matched = true
+				if !slices.Contains(selected, contextName) {
+					selected = append(selected, contextName)
+				}
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("no context matches %s", pattern)
+		}
+	}
+	slices.Sort(selected)
+
+	return selected, nil
+}
+
+// contextClient creates a client for a context and returns it together with the namespace given with --namespace
+// or else the namespace of the context. It does not change the current context.
+func contextClient(contextName string) (*internal.Client, string, error) {
+	namespace, err := resolveNamespace(contextName)
+	if err != nil {
+		return nil, "", err
+	}
+	c, err := newClient(contextName)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return c, namespace, nil
+}
+
+// fanOut runs query concurrently for each of the contexts and returns the results in the order of the contexts.
+func fanOut[T any](contexts []string, query func(contextName string) (T, error)) []fanoutResult[T] {
+	results := make([]fanoutResult[T], len(contexts))
+	var wg sync.WaitGroup
+	for i, contextName := range contexts {
+		wg.Go(func() {
+			value, err := query(contextName)
+			results[i] = fanoutResult[T]{Context: contextName, Value: value, Err: err}
+		})
+	}
+	wg.Wait()
+
+	return results
+}
+
+// reportFanoutErrors prints the errors of the contexts that failed to stderr.
+// It returns an error if any context failed, so the command exits with a non-zero status.
+func reportFanoutErrors[T any](results []fanoutResult[T]) error {
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			fmt.Fprintf(os.Stderr, "Error: context %s: %v\n", result.Context, result.Err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("query failed for %d of %d contexts", failed, len(results))
+	}
+
+	return nil
+}
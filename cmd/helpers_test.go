@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/wim-vdw/wimkube/internal"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// testKubeConfig has two contexts on clusters that are never contacted, because the tests use fake clients.
+const testKubeConfig = `apiVersion: v1
+kind: Config
+current-context: dev
+clusters:
+- name: dev
+  cluster: {server: "https://dev.example.com"}
+- name: prod
+  cluster: {server: "https://prod.example.com"}
+contexts:
+- name: dev
+  context: {cluster: dev, user: dev, namespace: default}
+- name: prod
+  context: {cluster: prod, user: prod, namespace: payments}
+users:
+- name: dev
+  user: {token: dev}
+- name: prod
+  user: {token: prod}
+`
+
+// testEnv runs the commands against fake clientsets instead of real clusters.
+type testEnv struct {
+	kubeconfig string
+	clientsets map[string]*fake.Clientset
+	dynamics   map[string]*dynamicfake.FakeDynamicClient
+}
+
+// newTestEnv writes a kubeconfig to a temporary home directory and replaces the client factory with one that returns
+// a fake client per context, holding the objects of that context. Unstructured objects, e.g. custom resources, are
+// only held by the fake dynamic client. Clients created by earlier tests are dropped.
+func newTestEnv(t *testing.T, objects map[string][]runtime.Object) *testEnv {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	kubeconfig := filepath.Join(home, "config")
+	if err := os.WriteFile(kubeconfig, []byte(testKubeConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	env := &testEnv{kubeconfig: kubeconfig, clientsets: map[string]*fake.Clientset{}, dynamics: map[string]*dynamicfake.FakeDynamicClient{}}
+	for _, contextName := range []string{"dev", "prod"} {
+		var typed []runtime.Object
+		for _, object := range objects[contextName] {
+			if _, isUnstructured := object.(*unstructured.Unstructured); !isUnstructured {
+				typed = append(typed, object)
+			}
+		}
+		env.clientsets[contextName] = fake.NewClientset(typed...)
+		env.dynamics[contextName] = dynamicfake.NewSimpleDynamicClient(scheme.Scheme, objects[contextName]...)
+	}
+	factory := createClient
+	createClient = func(contextName string) (*internal.Client, error) {
+		return internal.NewClientFromInterfaces(env.clientsets[contextName], env.dynamics[contextName], time.Second), nil
+	}
+	clients = map[string]*clientEntry{}
+	permissions = map[accessKey]bool{}
+	t.Cleanup(func() {
+		createClient = factory
+		clients = map[string]*clientEntry{}
+		permissions = map[accessKey]bool{}
+	})
+
+	return env
+}
+
+// run executes wimkube with args against the kubeconfig of the test environment.
+// It returns what the command wrote to stdout and the error of the command.
+func (env *testEnv) run(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	t.Cleanup(func() { resetFlags(rootCmd) })
+
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	output := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+		output <- buf.String()
+	}()
+
+	rootCmd.SetArgs(append([]string{"--kubeconfig", env.kubeconfig}, args...))
+	rootCmd.SetErr(io.Discard)
+	err = rootCmd.Execute()
+
+	_ = w.Close()
+	os.Stdout = stdout
+
+	return <-output, err
+}
+
+// resetFlags sets the flags of a command and its subcommands back to their defaults, because the commands and the
+// variables bound to their flags are shared by all tests.
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if s, ok := f.Value.(pflag.SliceValue); ok {
+			_ = s.Replace(nil)
+		} else {
+			_ = f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, sub := range cmd.Commands() {
+		resetFlags(sub)
+	}
+}
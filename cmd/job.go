@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"charm.land/huh/v2"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+var (
+	jobLogsContainer string
+	jobLogsFollow    bool
+)
+
+var jobCmd = &cobra.Command{
+	Use:   "job",
+	Short: "Manage jobs.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return loopMenu(showJobMenu)
+	},
+}
+
+var jobListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all jobs.",
+	RunE:  execJobList,
+}
+
+var jobLogsCmd = &cobra.Command{
+	Use:   "logs [job-name]",
+	Short: "Get the logs of the most recent pod of a job.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  execJobLogs,
+}
+
+func showJobMenu() error {
+	ctx := commandContext(nil)
+	var option string
+	c, _, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("Select an option (namespace: %s)", currentNamespace)
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title(title).
+				Options(
+					huh.NewOption("List all jobs", "1"),
+					huh.NewOption("Get the logs of a job", "2"),
+				).
+				Value(&option),
+		),
+	)
+	err = runMenu(form)
+	if err != nil {
+		return err
+	}
+	switch option {
+	case "1":
+		return execJobList(nil, nil)
+	case "2":
+		jobs, err := c.GetJobs(ctx, currentNamespace)
+		if err != nil {
+			return err
+		}
+		if len(jobs) == 0 {
+			fmt.Printf("No resources found in %s namespace.\n", currentNamespace)
+			return nil
+		}
+		jobNames := make([]string, 0, len(jobs))
+		for _, job := range jobs {
+			jobNames = append(jobNames, job.Name)
+		}
+		title = fmt.Sprintf("Select a job (namespace: %s)", currentNamespace)
+		jobName, err := pickValue(title, jobNames, "", "")
+		if err != nil {
+			return err
+		}
+		podName, containers, err := c.GetJobPod(ctx, currentNamespace, jobName)
+		if err != nil {
+			return err
+		}
+		jobLogsContainer = containers[0]
+		if len(containers) > 1 {
+			title = fmt.Sprintf("Select a container (namespace: %s, pod: %s)", currentNamespace, podName)
+			jobLogsContainer, err = pickValue(title, containers, jobLogsContainer, "")
+			if err != nil {
+				return err
+			}
+		}
+		return execJobLogs(nil, []string{jobName})
+	}
+
+	return nil
+}
+
+func execJobList(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	c, _, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	jobs, err := c.GetJobs(ctx, currentNamespace)
+	if err != nil {
+		return err
+	}
+	if len(jobs) == 0 {
+		fmt.Printf("No resources found in %s namespace.\n", currentNamespace)
+		return nil
+	}
+	w := newTableWriter()
+	fmt.Fprintln(w, "NAME\tSTATUS\tCOMPLETIONS\tDURATION\tAGE")
+	for _, job := range jobs {
+		jobDuration := "<none>"
+		if job.Duration > 0 {
+			jobDuration = duration.HumanDuration(job.Duration)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d/%d\t%s\t%s\n", job.Name, job.Status, job.Succeeded, job.Completions, jobDuration, formatAge(job.Created))
+	}
+
+	return w.Flush()
+}
+
+func execJobLogs(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	jobName := args[0]
+	c, _, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	podName, containers, err := c.GetJobPod(ctx, currentNamespace, jobName)
+	if err != nil {
+		return err
+	}
+	containerName := jobLogsContainer
+	if containerName == "" {
+		containerName = containers[0]
+	} else if !slices.Contains(containers, containerName) {
+		return fmt.Errorf("container '%s' does not exist in pod %s of job %s", containerName, podName, jobName)
+	}
+
+	return c.StreamPodLogs(ctx, currentNamespace, podName, containerName, jobLogsFollow, os.Stdout)
+}
+
+func init() {
+	rootCmd.AddCommand(jobCmd)
+	jobCmd.AddCommand(jobListCmd)
+	jobCmd.AddCommand(jobLogsCmd)
+	jobLogsCmd.Flags().StringVar(&jobLogsContainer, "container", "", "Container to get the logs of (defaults to the first container).")
+	jobLogsCmd.Flags().BoolVarP(&jobLogsFollow, "follow", "f", false, "Stream the logs until the container terminates.")
+}
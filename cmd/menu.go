@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/huh/v2"
+)
+
+var (
+	// errBack is returned by runForm when the user presses Esc in a picker or prompt of a menu.
+	errBack = errors.New("back")
+	// errMenuBack is returned by runMenu when the user presses Esc in a menu.
+	errMenuBack = errors.New("back to previous menu")
+)
+
+// menuDepth is the number of menus that are running. Esc in a picker or prompt goes back to the menu, and aborts a
+// command that runs without a menu.
+var menuDepth int
+
+type menuEntry struct {
+	title string
+	show  func() error
+}
+
+// mainMenu lists the menus that can be reached from the main menu, in the order they are shown.
+var mainMenu = []menuEntry{
+	{"Contexts", showContextMenu},
+	{"Namespaces", showNamespaceMenu},
+	{"Pods", showPodMenu},
+	{"Services", showServiceMenu},
+	{"ConfigMaps", showConfigMapMenu},
+	{"Secrets", showSecretMenu},
+	{"Nodes", showNodeMenu},
+	{"Jobs", showJobMenu},
+	{"CronJobs", showCronJobMenu},
+	{"Resources", showResourceMenu},
+	{"Auth", showAuthMenu},
+}
+
+// showMainMenu shows the top-level menu until the user quits.
+// Each entry opens its own menu, which returns to the main menu when the user presses Esc.
+func showMainMenu() error {
+	for {
+		currentContext, _ := resolveContext()
+		currentNamespace, _ := resolveNamespace(currentContext)
+		options := make([]huh.Option[int], 0, len(mainMenu)+1)
+		for i, entry := range mainMenu {
+			options = append(options, huh.NewOption(entry.title, i))
+		}
+		options = append(options, huh.NewOption("Quit", -1))
+		// The cursor starts on the option with the bound value, so on the first menu instead of on Quit.
+		option := 0
+		form := huh.NewForm(
+			huh.NewGroup(
+				huh.NewSelect[int]().
+					Title(fmt.Sprintf("wimkube (context: %s, namespace: %s)", currentContext, currentNamespace)).
+					Options(options...).
+					Value(&option),
+			),
+		)
+		err := runMenu(form)
+		if errors.Is(err, errMenuBack) || (err == nil && option == -1) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := loopMenu(mainMenu[option].show); err != nil {
+			return err
+		}
+	}
+}
+
+// loopMenu shows a menu again after each action until the user presses Esc in the menu or quits with Ctrl+C.
+// Errors of an action are printed and do not end the loop.
+func loopMenu(show func() error) error {
+	menuDepth++
+	defer func() { menuDepth-- }()
+	for {
+		err := show()
+		switch {
+		case errors.Is(err, errMenuBack):
+			return nil
+		case errors.Is(err, huh.ErrUserAborted):
+			return err
+		case rootContext.Err() != nil:
+			// wimkube was interrupted or terminated while the menu was running an operation.
+			return rootContext.Err()
+		case err != nil && !errors.Is(err, errBack):
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		fmt.Println()
+	}
+}
+
+// runMenu runs the form of a menu.
+// It returns errMenuBack if the user presses Esc.
+func runMenu(form *huh.Form) error {
+	menuDepth++
+	defer func() { menuDepth-- }()
+	err := runForm(form)
+	if errors.Is(err, errBack) {
+		return errMenuBack
+	}
+
+	return err
+}
+
+// runForm runs a form in which Esc goes back one level instead of being ignored.
+// It returns the error of escape if the user presses Esc and huh.ErrUserAborted if the user presses Ctrl+C.
+func runForm(form *huh.Form) error {
+	keyMap := huh.NewDefaultKeyMap()
+	keyMap.Quit = key.NewBinding(key.WithKeys("ctrl+c", "esc"))
+	var back bool
+	form = form.WithKeyMap(keyMap).WithProgramOptions(tea.WithFilter(func(_ tea.Model, msg tea.Msg) tea.Msg {
+		if k, ok := msg.(tea.KeyPressMsg); ok {
+			back = k.String() == "esc"
+		}
+		return msg
+	}))
+	err := form.Run()
+	if back && errors.Is(err, huh.ErrUserAborted) {
+		return escape()
+	}
+
+	return err
+}
+
+// escape returns the error for Esc in a picker or prompt: errBack in a menu, which goes back to the menu, and
+// errAborted in a command, which ends it like declining a confirmation.
+func escape() error {
+	if menuDepth > 0 {
+		return errBack
+	}
+
+	return errAborted
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEscape(t *testing.T) {
+	if err := escape(); !errors.Is(err, errAborted) {
+		t.Errorf("escape() in a command = %v, want %v", err, errAborted)
+	}
+	var inMenu error
+	err := loopMenu(func() error {
+		inMenu = escape()
+		return errMenuBack
+	})
+	if err != nil {
+		t.Fatalf("loopMenu() error = %v", err)
+	}
+	if !errors.Is(inMenu, errBack) {
+		t.Errorf("escape() in a menu = %v, want %v", inMenu, errBack)
+	}
+	if menuDepth != 0 {
+		t.Errorf("menuDepth = %d after the menu, want 0", menuDepth)
+	}
+}
@@ -1,7 +1,15 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
 
 	"charm.land/huh/v2"
 	"github.com/spf13/cobra"
@@ -9,18 +17,33 @@ import (
 	"github.com/wim-vdw/wimkube/internal"
 )
 
+// immortalNamespaces are the namespaces the API server refuses to delete, and kube-node-lease, whose deletion
+// breaks the heartbeats of the nodes.
+var immortalNamespaces = []string{"default", "kube-system", "kube-public", "kube-node-lease"}
+
+var (
+	namespaceSetForce          bool
+	namespaceSetDescribe       bool
+	namespaceCreateLabels      map[string]string
+	namespaceCreateAnnotations map[string]string
+	namespaceCreateSwitch      bool
+	namespaceDeleteConfirm     string
+	namespaceDeleteTimeout     time.Duration
+)
+
 var namespaceCmd = &cobra.Command{
 	Use:   "namespace",
 	Short: "Manage namespaces.",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return showNamespaceMenu()
+		return loopMenu(showNamespaceMenu)
 	},
 }
 
 var namespaceListCmd = &cobra.Command{
-	Use:   "list",
-	Short: "List all namespaces.",
-	RunE:  execNamespaceList,
+	Use:         "list",
+	Short:       "List all namespaces.",
+	Annotations: map[string]string{fanoutAnnotation: "true"},
+	RunE:        execNamespaceList,
 }
 
 var namespaceGetCmd = &cobra.Command{
@@ -32,25 +55,55 @@ var namespaceGetCmd = &cobra.Command{
 var namespaceSetCmd = &cobra.Command{
 	Use:   "set [namespace]",
 	Short: "Set current namespace.",
-	Args:  cobra.ExactArgs(1),
+	Args:  cobra.MaximumNArgs(1),
 	RunE:  execNamespaceSet,
 }
 
+var namespaceDescribeCmd = &cobra.Command{
+	Use:   "describe [namespace]",
+	Short: "Describe a namespace, what it contains and its quotas and limits.",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  execNamespaceDescribe,
+}
+
+var namespaceCreateCmd = &cobra.Command{
+	Use:   "create [namespace]",
+	Short: "Create a namespace.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  execNamespaceCreate,
+}
+
+var namespaceDeleteCmd = &cobra.Command{
+	Use:   "delete [namespace]",
+	Short: "Delete a namespace and everything in it.",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  execNamespaceDelete,
+}
+
 func showNamespaceMenu() error {
-	var option, namespace string
+	ctx := commandContext(nil)
+	var option string
+	c, _, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	options := menuOptions(ctx, c, currentNamespace, []menuAction{
+		{"Get current namespace", "1", nil},
+		{"List all namespaces", "2", nil},
+		{"Set current namespace", "3", nil},
+		{"Describe a namespace", "4", nil},
+		{"Create a namespace", "5", &accessCreateNamespace},
+		{"Delete a namespace", "6", &accessDeleteNamespace},
+	})
 	form := huh.NewForm(
 		huh.NewGroup(
 			huh.NewSelect[string]().
 				Title("Select an option").
-				Options(
-					huh.NewOption("Get current namespace", "1"),
-					huh.NewOption("List all namespaces", "2"),
-					huh.NewOption("Set current namespace", "3"),
-				).
+				Options(options...).
 				Value(&option),
 		),
 	)
-	err := form.Run()
+	err = runMenu(form)
 	if err != nil {
 		return err
 	}
@@ -60,49 +113,49 @@ func showNamespaceMenu() error {
 	case "2":
 		return execNamespaceList(nil, nil)
 	case "3":
-		currentContext, err := kubeConfig.GetCurrentContext()
-		if err != nil {
-			return err
-		}
-		c, err := internal.NewClient(viper.GetString("kubeconfig"), currentContext)
-		if err != nil {
-			return err
-		}
-		namespaces, err := c.GetNamespaces()
-		if err != nil {
-			return err
-		}
-		currentNamespace, _ := kubeConfig.GetCurrentNamespace()
-		namespace = currentNamespace
-		title := fmt.Sprintf("Select a namespace (context: %s)", currentContext)
+		return execNamespaceSet(nil, nil)
+	case "4":
+		return execNamespaceDescribe(nil, nil)
+	case "5":
+		var namespace string
 		form := huh.NewForm(
 			huh.NewGroup(
-				huh.NewSelect[string]().
-					Title(title).
-					Options(huh.NewOptions(namespaces...)...).
+				huh.NewInput().
+					Title("Enter the name of the namespace").
+					Validate(huh.ValidateNotEmpty()).
 					Value(&namespace),
 			),
 		)
-		err = form.Run()
+		if err := runForm(form); err != nil {
+			return err
+		}
+		namespaceCreateSwitch, err = confirm(fmt.Sprintf("Switch to namespace %s afterwards?", strings.TrimSpace(namespace)))
 		if err != nil {
 			return err
 		}
-		return execNamespaceSet(nil, []string{namespace})
+		defer func() { namespaceCreateSwitch = false }()
+		return execNamespaceCreate(nil, []string{strings.TrimSpace(namespace)})
+	case "6":
+		return execNamespaceDelete(nil, nil)
 	}
 
 	return nil
 }
 
 func execNamespaceList(cmd *cobra.Command, args []string) error {
-	currentContext, err := kubeConfig.GetCurrentContext()
+	ctx := commandContext(cmd)
+	if isFanout() {
+		return execNamespaceListFanout(ctx)
+	}
+	currentContext, err := resolveContext()
 	if err != nil {
 		return err
 	}
-	c, err := internal.NewClient(viper.GetString("kubeconfig"), currentContext)
+	c, err := newClient(currentContext)
 	if err != nil {
 		return err
 	}
-	namespaces, err := c.GetNamespaces()
+	namespaces, err := c.GetNamespaces(ctx)
 	if err != nil {
 		return err
 	}
@@ -113,8 +166,39 @@ func execNamespaceList(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func execNamespaceListFanout(ctx context.Context) error {
+	contexts, err := selectedContexts()
+	if err != nil {
+		return err
+	}
+	results := fanOut(contexts, func(contextName string) ([]string, error) {
+		c, _, err := contextClient(contextName)
+		if err != nil {
+			return nil, err
+		}
+		return c.GetNamespaces(ctx)
+	})
+
+	w := newTableWriter()
+	fmt.Fprintln(w, "CONTEXT\tNAMESPACE")
+	for _, result := range results {
+		for _, namespace := range result.Value {
+			fmt.Fprintf(w, "%s\t%s\n", result.Context, namespace)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	return reportFanoutErrors(results)
+}
+
 func execNamespaceGet(cmd *cobra.Command, args []string) error {
-	currentNamespace, err := kubeConfig.GetCurrentNamespace()
+	currentContext, err := resolveContext()
+	if err != nil {
+		return err
+	}
+	currentNamespace, err := resolveNamespace(currentContext)
 	if err != nil {
 		return err
 	}
@@ -124,19 +208,514 @@ func execNamespaceGet(cmd *cobra.Command, args []string) error {
 }
 
 func execNamespaceSet(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	currentContext, err := resolveContext()
+	if err != nil {
+		return err
+	}
+	var namespace string
+	if len(args) == 1 {
+		namespace = args[0]
+		if !namespaceSetForce {
+			if err := checkNamespace(ctx, namespace); err != nil {
+				return err
+			}
+		}
+	} else {
+		namespace, err = selectNamespace(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	err = kubeConfig.SetContextNamespace(currentContext, namespace)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Namespace of context %s set to: %s\n", currentContext, namespace)
+	if !namespaceSetDescribe && !viper.GetBool("describe-namespace-on-set") {
+		return nil
+	}
+	fmt.Println()
+
+	return describeNamespace(ctx, namespace)
+}
+
+func execNamespaceDescribe(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	_, _, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	namespace := currentNamespace
+	if len(args) == 1 {
+		namespace = args[0]
+	} else if cmd == nil {
+		namespace, err = selectNamespace(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return describeNamespace(ctx, namespace)
+}
+
+// podPhases is the order in which the pods of a namespace are counted by phase.
+var podPhases = []string{"Running", "Pending", "Succeeded", "Failed", "Unknown"}
+
+// describeNamespace prints the labels and pod security levels of a namespace, how many resources it contains, and
+// the usage of its ResourceQuotas and the constraints of its LimitRanges.
+func describeNamespace(ctx context.Context, namespace string) error {
+	c, currentContext, _, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	details, err := c.GetNamespaceDetails(ctx, namespace)
+	if internal.IsNotFound(err) {
+		return fmt.Errorf("namespace %s does not exist in context %s", namespace, currentContext)
+	}
+	if err != nil {
+		return err
+	}
+
+	w := newTableWriter()
+	fmt.Fprintf(w, "Name:\t%s\n", details.Name)
+	fmt.Fprintf(w, "Status:\t%s\n", details.Status)
+	fmt.Fprintf(w, "Age:\t%s\n", formatAge(details.Created))
+	printMap(w, "Labels:", details.Labels)
+	printMap(w, "Annotations:", details.Annotations)
+	fmt.Fprintf(w, "Pod security:\t%s\n", formatPodSecurity(details.Labels))
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Println("Resources:")
+	w = newTableWriter()
+	for _, resource := range details.Resources {
+		switch {
+		case resource.Err != nil:
+			fmt.Fprintf(w, "  %s\t%s\n", resource.Resource, listError(resource.Err))
+		case resource.Resource == "pods" && len(details.PodPhases) > 0:
+			fmt.Fprintf(w, "  %s\t%d (%s)\n", resource.Resource, resource.Count, formatPodPhases(details.PodPhases))
+		default:
+			fmt.Fprintf(w, "  %s\t%d\n", resource.Resource, resource.Count)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	switch {
+	case details.QuotasErr != nil:
+		fmt.Printf("Resource quotas: %s\n", listError(details.QuotasErr))
+	case len(details.Quotas) == 0:
+		fmt.Println("Resource quotas: <none>")
+	default:
+		fmt.Println("Resource quotas:")
+	}
+	for _, quota := range details.Quotas {
+		fmt.Printf("  %s\n", quota.Name)
+		w := newTableWriter()
+		fmt.Fprintln(w, "    RESOURCE\tUSED\tHARD")
+		for _, resource := range quota.Resources {
+			fmt.Fprintf(w, "    %s\t%s\t%s\n", resource.Resource, resource.Used, resource.Hard)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case details.LimitRangesErr != nil:
+		fmt.Printf("Limit ranges: %s\n", listError(details.LimitRangesErr))
+	case len(details.LimitRanges) == 0:
+		fmt.Println("Limit ranges: <none>")
+	default:
+		fmt.Println("Limit ranges:")
+	}
+	for _, limitRange := range details.LimitRanges {
+		fmt.Printf("  %s\n", limitRange.Name)
+		w := newTableWriter()
+		fmt.Fprintln(w, "    TYPE\tRESOURCE\tMIN\tMAX\tDEFAULT REQUEST\tDEFAULT LIMIT")
+		for _, limit := range limitRange.Limits {
+			fmt.Fprintf(w, "    %s\t%s\t%s\t%s\t%s\t%s\n", limit.Type, limit.Resource,
+				valueOrDash(limit.Min), valueOrDash(limit.Max), valueOrDash(limit.DefaultRequest), valueOrDash(limit.Default))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// listError returns what to print instead of resources that could not be listed.
+func listError(err error) string {
+	if internal.IsForbidden(err) {
+		return "<not allowed to list>"
+	}
+
+	return "<unknown>"
+}
+
+// printMap prints the sorted keys and values of a map below each other, the first one after the title.
+func printMap(w io.Writer, title string, m map[string]string) {
+	if len(m) == 0 {
+		fmt.Fprintf(w, "%s\t<none>\n", title)
+		return
+	}
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		fmt.Fprintf(w, "%s\t%s=%s\n", title, key, m[key])
+		title = ""
+	}
+}
+
+// formatPodSecurity returns the Pod Security Admission levels set by the labels of a namespace, e.g.
+// "enforce=restricted, warn=baseline:v1.30", or "<none> (privileged)" if none are set.
+func formatPodSecurity(labels map[string]string) string {
+	var modes []string
+	for _, mode := range []string{"enforce", "audit", "warn"} {
+		level, exists := labels["pod-security.kubernetes.io/"+mode]
+		if !exists {
+			continue
+		}
+		if version := labels["pod-security.kubernetes.io/"+mode+"-version"]; version != "" && version != "latest" {
+			level += ":" + version
+		}
+		modes = append(modes, mode+"="+level)
+	}
+	if len(modes) == 0 {
+		return "<none> (privileged)"
+	}
+
+	return strings.Join(modes, ", ")
+}
+
+// formatPodPhases returns the number of pods by phase, e.g. "10 Running, 1 Pending".
+func formatPodPhases(phases map[string]int) string {
+	var counts []string
+	for _, phase := range podPhases {
+		if phases[phase] > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", phases[phase], phase))
+		}
+	}
+	for _, phase := range slices.Sorted(maps.Keys(phases)) {
+		if !slices.Contains(podPhases, phase) {
+			counts = append(counts, fmt.Sprintf("%d %s", phases[phase], valueOrNone(phase)))
+		}
+	}
+
+	return strings.Join(counts, ", ")
+}
+
+func execNamespaceCreate(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
 	namespace := args[0]
-	err := kubeConfig.SetNamespace(namespace)
+	c, currentContext, _, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	if err := c.CreateNamespace(ctx, namespace, namespaceCreateLabels, namespaceCreateAnnotations); err != nil {
+		return err
+	}
+	fmt.Printf("Namespace %s created in context %s.\n", namespace, currentContext)
+	if !namespaceCreateSwitch {
+		return nil
+	}
+	if err := kubeConfig.SetContextNamespace(currentContext, namespace); err != nil {
+		return err
+	}
+	fmt.Printf("Namespace of context %s set to: %s\n", currentContext, namespace)
+
+	return nil
+}
+
+func execNamespaceDelete(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	c, currentContext, _, err := resolveTarget()
 	if err != nil {
 		return err
 	}
-	fmt.Printf("Current namespace set to: %s\n", namespace)
+	var namespace string
+	if len(args) == 1 {
+		namespace = args[0]
+	} else {
+		namespace, err = selectNamespace(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	if slices.Contains(immortalNamespaces, namespace) {
+		return fmt.Errorf("namespace %s is needed by the cluster and cannot be deleted", namespace)
+	}
+	if err := checkAccess(ctx, c, currentContext, "", accessDeleteNamespace); err != nil {
+		return err
+	}
+
+	summary, err := c.GetNamespaceSummary(ctx, namespace)
+	if internal.IsNotFound(err) {
+		return fmt.Errorf("namespace %s does not exist in context %s", namespace, currentContext)
+	}
+	if err != nil {
+		return err
+	}
+	if err := printNamespaceContents(summary); err != nil {
+		return err
+	}
+	if summary.Status == "Terminating" {
+		fmt.Printf("Namespace %s is already being deleted.\n", namespace)
+	} else {
+		if err := confirmProtectedContext(currentContext, "delete namespace "+namespace); err != nil {
+			return err
+		}
+		switch {
+		case namespaceDeleteConfirm == "":
+			if err := confirmName(fmt.Sprintf("Delete namespace %s and everything in it from context %s?", namespace, currentContext), namespace); err != nil {
+				return err
+			}
+		case namespaceDeleteConfirm != namespace:
+			return fmt.Errorf("--confirm %s does not match namespace %s", namespaceDeleteConfirm, namespace)
+		}
+		if err := c.DeleteNamespace(ctx, namespace); err != nil {
+			return err
+		}
+		fmt.Printf("Namespace %s is being deleted.\n", namespace)
+	}
+	if err := resetDeletedNamespace(currentContext, namespace); err != nil {
+		return err
+	}
+	if namespaceDeleteTimeout <= 0 {
+		return nil
+	}
+
+	start := time.Now()
+	remaining, err := c.WaitForNamespaceDeleted(ctx, namespace, namespaceDeleteTimeout, func(msg string) {
+		fmt.Printf("[%5s] %s\n", time.Since(start).Round(time.Second), msg)
+	})
+	if err != nil {
+		return err
+	}
+	if remaining != nil {
+		if len(remaining.Finalizers) > 0 {
+			fmt.Printf("Finalizers of namespace %s: %s\n", namespace, strings.Join(remaining.Finalizers, ", "))
+		}
+		return fmt.Errorf("namespace %s is still terminating after %s", namespace, namespaceDeleteTimeout)
+	}
+	fmt.Printf("Namespace %s deleted.\n", namespace)
+
+	return nil
+}
+
+// printNamespaceContents prints how many resources of each type a namespace contains.
+func printNamespaceContents(summary *internal.NamespaceSummary) error {
+	w := newTableWriter()
+	empty := true
+	for _, resource := range summary.Resources {
+		switch {
+		case resource.Err != nil:
+			fmt.Fprintf(w, "  %s\t%s\n", resource.Resource, listError(resource.Err))
+		case resource.Count > 0:
+			fmt.Fprintf(w, "  %s\t%d\n", resource.Resource, resource.Count)
+		default:
+			continue
+		}
+		empty = false
+	}
+	if empty {
+		fmt.Printf("Namespace %s (%s) contains none of the common resources.\n", summary.Name, summary.Status)
+		return nil
+	}
+	fmt.Printf("Namespace %s (%s) contains:\n", summary.Name, summary.Status)
+
+	return w.Flush()
+}
+
+// resetDeletedNamespace sets the namespace of a context back to default if it is the deleted namespace.
+func resetDeletedNamespace(contextName, namespace string) error {
+	configured, err := kubeConfig.GetContextNamespace(contextName)
+	if err != nil || configured != namespace {
+		return err
+	}
+	if err := kubeConfig.SetContextNamespace(contextName, "default"); err != nil {
+		return err
+	}
+	fmt.Printf("Namespace of context %s set to: default\n", contextName)
 
 	return nil
 }
 
+// contextNamespaces lists the namespaces of the contexts matching a glob pattern, for users that are not allowed to
+// list the namespaces of the cluster.
+type contextNamespaces struct {
+	Context    string   `mapstructure:"context"`
+	Namespaces []string `mapstructure:"namespaces"`
+}
+
+// configuredNamespaces returns the namespaces listed for the context under context-namespaces in the configuration file.
+func configuredNamespaces(contextName string) []string {
+	var entries []contextNamespaces
+	if err := viper.UnmarshalKey("context-namespaces", &entries); err != nil {
+		return nil
+	}
+	var namespaces []string
+	for _, entry := range entries {
+		if matched, _ := path.Match(entry.Context, contextName); !matched {
+			continue
+		}
+		for _, namespace := range entry.Namespaces {
+			if !slices.Contains(namespaces, namespace) {
+				namespaces = append(namespaces, namespace)
+			}
+		}
+	}
+
+	return namespaces
+}
+
+// listNamespaces returns the namespaces of the cluster of a context, from the cache if they were listed recently.
+// If the user is not allowed to list them, it returns the namespaces configured for the context instead, or an empty
+// list if there are none.
+func listNamespaces(ctx context.Context, c *internal.Client, contextName string) ([]string, error) {
+	namespaces, err := c.GetCachedNamespaces(ctx)
+	if internal.IsForbidden(err) {
+		return configuredNamespaces(contextName), nil
+	}
+
+	return namespaces, err
+}
+
+// checkNamespace returns an error suggesting close matches if the namespace does not exist in the current context.
+// If the user is not allowed to look up the namespace, only a warning is printed.
+func checkNamespace(ctx context.Context, namespace string) error {
+	c, currentContext, _, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	exists, err := c.NamespaceExists(ctx, namespace)
+	if internal.IsForbidden(err) {
+		if !slices.Contains(configuredNamespaces(currentContext), namespace) {
+			fmt.Fprintf(os.Stderr, "Warning: unable to verify that namespace %s exists: %v\n", namespace, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	msg := fmt.Sprintf("namespace %s does not exist in context %s", namespace, currentContext)
+	namespaces, _ := listNamespaces(ctx, c, currentContext)
+	if matches := closeMatches(namespace, namespaces); len(matches) > 0 {
+		msg += fmt.Sprintf(", did you mean %s?", strings.Join(matches, ", "))
+	}
+
+	return fmt.Errorf("%s (use --force to set it anyway)", msg)
+}
+
+// selectNamespace lets the user pick one of the namespaces of the current context.
+// If the namespaces cannot be listed and none are configured for the context, the user can type the namespace instead.
+func selectNamespace(ctx context.Context) (string, error) {
+	currentContext, err := resolveContext()
+	if err != nil {
+		return "", err
+	}
+	c, err := newClient(currentContext)
+	if err != nil {
+		return "", err
+	}
+	namespaces, err := listNamespaces(ctx, c, currentContext)
+	if err != nil {
+		return "", err
+	}
+	currentNamespace, _ := resolveNamespace(currentContext)
+	if len(namespaces) == 0 {
+		return inputNamespace(currentContext, currentNamespace)
+	}
+	title := fmt.Sprintf("Select a namespace (context: %s)", currentContext)
+
+	return pickValue(title, namespaces, currentNamespace, "")
+}
+
+// inputNamespace asks the user to type the name of a namespace.
+func inputNamespace(contextName, currentNamespace string) (string, error) {
+	namespace := currentNamespace
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title(fmt.Sprintf("Enter a namespace (context: %s)", contextName)).
+				Description("You are not allowed to list the namespaces of this cluster.").
+				Validate(huh.ValidateNotEmpty()).
+				Value(&namespace),
+		),
+	)
+	if err := runForm(form); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(namespace), nil
+}
+
+// closeMatches returns up to three candidates that are within a small edit distance of name, closest first.
+func closeMatches(name string, candidates []string) []string {
+	maxDistance := max(2, len(name)/3)
+	type match struct {
+		candidate string
+		distance  int
+	}
+	var matches []match
+	for _, candidate := range candidates {
+		distance := levenshtein(name, candidate)
+		if distance <= maxDistance || strings.Contains(candidate, name) {
+			matches = append(matches, match{candidate, distance})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b match) int {
+		return a.distance - b.distance
+	})
+	out := make([]string, 0, 3)
+	for _, m := range matches[:min(len(matches), 3)] {
+		out = append(out, m.candidate)
+	}
+
+	return out
+}
+
+// levenshtein returns the number of single character edits needed to change a into b.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
 func init() {
 	rootCmd.AddCommand(namespaceCmd)
 	namespaceCmd.AddCommand(namespaceListCmd)
 	namespaceCmd.AddCommand(namespaceGetCmd)
 	namespaceCmd.AddCommand(namespaceSetCmd)
+	namespaceCmd.AddCommand(namespaceDescribeCmd)
+	namespaceCmd.AddCommand(namespaceCreateCmd)
+	namespaceCmd.AddCommand(namespaceDeleteCmd)
+	namespaceSetCmd.Flags().BoolVar(&namespaceSetForce, "force", false, "Set the namespace without checking that it exists.")
+	namespaceSetCmd.Flags().BoolVar(&namespaceSetDescribe, "describe", false, "Describe the namespace after setting it.")
+	namespaceCreateCmd.Flags().StringToStringVarP(&namespaceCreateLabels, "label", "l", nil, "Labels of the namespace, e.g. team=payments,env=dev.")
+	namespaceCreateCmd.Flags().StringToStringVar(&namespaceCreateAnnotations, "annotation", nil, "Annotations of the namespace, e.g. owner=alice@example.com.")
+	namespaceCreateCmd.Flags().BoolVar(&namespaceCreateSwitch, "switch", false, "Set the new namespace as the namespace of the context.")
+	namespaceDeleteCmd.Flags().StringVar(&namespaceDeleteConfirm, "confirm", "", "Name of the namespace, to confirm the deletion without typing it, e.g. in scripts.")
+	namespaceDeleteCmd.Flags().DurationVar(&namespaceDeleteTimeout, "timeout", 2*time.Minute, "Time to wait until the namespace is gone, 0 means do not wait.")
 }
@@ -0,0 +1,317 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func namespaces(names ...string) []runtime.Object {
+	objects := make([]runtime.Object, 0, len(names))
+	for _, name := range names {
+		objects = append(objects, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+		})
+	}
+
+	return objects
+}
+
+func TestNamespaceCommands(t *testing.T) {
+	objects := map[string][]runtime.Object{
+		"dev":  namespaces("default", "kube-system"),
+		"prod": namespaces("default", "payments", "payments-staging"),
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "list namespaces of the current context",
+			args: []string{"namespace", "list"},
+			want: "default\nkube-system\n",
+		},
+		{
+			name: "list namespaces of another context",
+			args: []string{"namespace", "list", "--context", "prod"},
+			want: "default\npayments\npayments-staging\n",
+		},
+		{
+			name: "get namespace of the current context",
+			args: []string{"namespace", "get"},
+			want: "default\n",
+		},
+		{
+			name: "get namespace of another context",
+			args: []string{"namespace", "get", "-c", "prod"},
+			want: "payments\n",
+		},
+		{
+			name: "get namespace override",
+			args: []string{"namespace", "get", "-n", "kube-system"},
+			want: "kube-system\n",
+		},
+		{
+			name:    "get namespace of a missing context",
+			args:    []string{"namespace", "get", "-c", "staging"},
+			wantErr: "context 'staging' does not exist",
+		},
+		{
+			name: "set existing namespace",
+			args: []string{"namespace", "set", "kube-system"},
+			want: "Namespace of context dev set to: kube-system\n",
+		},
+		{
+			name:    "set missing namespace suggests close matches",
+			args:    []string{"namespace", "set", "-c", "prod", "paymnts"},
+			wantErr: "namespace paymnts does not exist in context prod, did you mean payments? (use --force to set it anyway)",
+		},
+		{
+			name: "set missing namespace with force",
+			args: []string{"namespace", "set", "--force", "paymnts"},
+			want: "Namespace of context dev set to: paymnts\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t, objects)
+			got, err := env.run(t, tt.args...)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNamespaceSetWritesKubeConfig(t *testing.T) {
+	env := newTestEnv(t, map[string][]runtime.Object{"prod": namespaces("payments", "orders")})
+	if _, err := env.run(t, "namespace", "set", "-c", "prod", "orders"); err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	data, err := os.ReadFile(env.kubeconfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "namespace: orders") || !strings.Contains(string(data), "current-context: dev") {
+		t.Errorf("kubeconfig does not set namespace orders for context prod only:\n%s", data)
+	}
+}
+
+func TestNamespaceDescribe(t *testing.T) {
+	payments := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "payments", Labels: map[string]string{
+			"team":                                       "payments",
+			"pod-security.kubernetes.io/enforce":         "restricted",
+			"pod-security.kubernetes.io/warn":            "baseline",
+			"pod-security.kubernetes.io/warn-version":    "v1.30",
+			"pod-security.kubernetes.io/enforce-version": "latest",
+		}},
+		Status: corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+	}
+	api := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "payments", Name: "api-1"}, Status: corev1.PodStatus{Phase: corev1.PodRunning}}
+	quota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "payments", Name: "compute"},
+		Status: corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")},
+			Used: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("1")},
+		},
+	}
+	env := newTestEnv(t, map[string][]runtime.Object{"prod": {payments, api, quota}})
+
+	got, err := env.run(t, "namespace", "describe", "-c", "prod")
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	for _, want := range []string{
+		"Name:           payments\n",
+		"Labels:         pod-security.kubernetes.io/enforce=restricted\n",
+		"Annotations:    <none>\n",
+		"Pod security:   enforce=restricted, warn=baseline:v1.30\n",
+		"  pods                     1 (1 Running)\n",
+		"  secrets                  0\n",
+		"    pods       1      10\n",
+		"Limit ranges: <none>\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestNamespaceDescribeForbidden(t *testing.T) {
+	env := newTestEnv(t, map[string][]runtime.Object{"dev": namespaces("default")})
+	for _, resource := range []string{"pods", "limitranges"} {
+		env.clientsets["dev"].PrependReactor("list", resource, func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: resource}, "", errors.New("forbidden by test"))
+		})
+	}
+
+	got, err := env.run(t, "namespace", "describe", "default")
+	if err != nil {
+		t.Fatalf("error = %v, want the description without pods and limit ranges", err)
+	}
+	for _, want := range []string{
+		"  pods                     <not allowed to list>\n",
+		"Resource quotas: <none>\n",
+		"Limit ranges: <not allowed to list>\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestNamespaceSetDescribe(t *testing.T) {
+	env := newTestEnv(t, map[string][]runtime.Object{"dev": namespaces("default")})
+	got, err := env.run(t, "namespace", "set", "default", "--describe")
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	if !strings.HasPrefix(got, "Namespace of context dev set to: default\n\nName:") || !strings.Contains(got, "Pod security:   <none> (privileged)\n") {
+		t.Errorf("output of set --describe = %q, want the description after setting the namespace", got)
+	}
+
+	if _, err := env.run(t, "namespace", "describe", "orders"); err == nil || !strings.Contains(err.Error(), "namespace orders does not exist in context dev") {
+		t.Errorf("error = %v, want namespace orders does not exist", err)
+	}
+}
+
+func TestNamespaceCreate(t *testing.T) {
+	env := newTestEnv(t, map[string][]runtime.Object{"prod": namespaces("default", "payments")})
+	got, err := env.run(t, "namespace", "create", "-c", "prod", "orders", "--label", "team=orders", "--annotation", "owner=alice", "--switch")
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	if want := "Namespace orders created in context prod.\nNamespace of context prod set to: orders\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+	ns, err := env.clientsets["prod"].CoreV1().Namespaces().Get(t.Context(), "orders", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("namespace orders was not created: %v", err)
+	}
+	if ns.Labels["team"] != "orders" || ns.Annotations["owner"] != "alice" {
+		t.Errorf("namespace orders has labels %v and annotations %v", ns.Labels, ns.Annotations)
+	}
+
+	if _, err := env.run(t, "namespace", "create", "-c", "prod", "payments"); err == nil {
+		t.Errorf("creating the existing namespace payments succeeded, want an error")
+	}
+}
+
+func TestNamespaceDelete(t *testing.T) {
+	objects := map[string][]runtime.Object{
+		"prod": append(namespaces("default", "payments", "orders"),
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "payments", Name: "api-1"}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "payments", Name: "api-2"}},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "payments", Name: "db"}},
+		),
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		allowed bool
+		want    []string
+		wantErr string
+	}{
+		{
+			name:    "delete the namespace of the context",
+			args:    []string{"namespace", "delete", "-c", "prod", "payments", "--confirm", "payments"},
+			allowed: true,
+			want: []string{
+				"Namespace payments (Active) contains:\n  pods      2\n  secrets   1\n",
+				"Namespace of context prod set to: default\n",
+				"Namespace payments deleted.\n",
+			},
+		},
+		{
+			name:    "confirmation does not match",
+			args:    []string{"namespace", "delete", "-c", "prod", "payments", "--confirm", "orders"},
+			allowed: true,
+			wantErr: "--confirm orders does not match namespace payments",
+		},
+		{
+			name:    "namespace needed by the cluster",
+			args:    []string{"namespace", "delete", "-c", "prod", "default", "--confirm", "default"},
+			allowed: true,
+			wantErr: "namespace default is needed by the cluster and cannot be deleted",
+		},
+		{
+			name:    "missing namespace",
+			args:    []string{"namespace", "delete", "-c", "prod", "billing", "--confirm", "billing"},
+			allowed: true,
+			wantErr: "namespace billing does not exist in context prod",
+		},
+		{
+			name:    "not allowed",
+			args:    []string{"namespace", "delete", "-c", "prod", "orders", "--confirm", "orders"},
+			wantErr: "you are not allowed to delete namespaces in the cluster of context prod (see wimkube auth can-i --list)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t, objects)
+			if tt.allowed {
+				allow(env.clientsets["prod"], "delete namespaces")
+			} else {
+				allow(env.clientsets["prod"])
+			}
+			got, err := env.run(t, tt.args...)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("output = %q, want it to contain %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCloseMatches(t *testing.T) {
+	candidates := []string{"default", "kube-system", "kube-public", "payments", "payments-staging"}
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{name: "defualt", want: []string{"default"}},
+		{name: "kube", want: []string{"kube-system", "kube-public"}},
+		{name: "payment", want: []string{"payments", "payments-staging"}},
+		{name: "orders", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := closeMatches(tt.name, candidates)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("closeMatches(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,366 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"charm.land/huh/v2"
+	"github.com/spf13/cobra"
+	"github.com/wim-vdw/wimkube/internal"
+)
+
+var (
+	drainOptions   internal.DrainOptions
+	nodeShellImage string
+)
+
+var nodeCmd = &cobra.Command{
+	Use:   "node",
+	Short: "Manage nodes.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return loopMenu(showNodeMenu)
+	},
+}
+
+var nodeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all nodes.",
+	RunE:  execNodeList,
+}
+
+var nodeDescribeCmd = &cobra.Command{
+	Use:   "describe [node-name]",
+	Short: "Describe a node.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  execNodeDescribe,
+}
+
+var nodeCordonCmd = &cobra.Command{
+	Use:   "cordon [node-name]",
+	Short: "Mark a node as unschedulable.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  execNodeCordon,
+}
+
+var nodeUncordonCmd = &cobra.Command{
+	Use:   "uncordon [node-name]",
+	Short: "Mark a node as schedulable.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  execNodeUncordon,
+}
+
+var nodeDrainCmd = &cobra.Command{
+	Use:   "drain [node-name]",
+	Short: "Cordon a node and evict all its pods, respecting PodDisruptionBudgets.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  execNodeDrain,
+}
+
+var nodeShellCmd = &cobra.Command{
+	Use:   "shell [node-name]",
+	Short: "Open a shell on a node through a short-lived privileged pod.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  execNodeShell,
+}
+
+func showNodeMenu() error {
+	ctx := commandContext(nil)
+	var option string
+	c, currentContext, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("Select an option (context: %s)", currentContext)
+	// The node shell pod is created in the current namespace, the other actions are cluster-wide.
+	options := menuOptions(ctx, c, currentNamespace, []menuAction{
+		{"List all nodes", "1", nil},
+		{"Describe a node", "2", nil},
+		{"Cordon a node", "3", &accessPatchNode},
+		{"Uncordon a node", "4", &accessPatchNode},
+		{"Drain a node", "5", &accessPatchNode},
+		{"Open a shell on a node", "6", &accessCreatePod},
+	})
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title(title).
+				Options(options...).
+				Value(&option),
+		),
+	)
+	err = runMenu(form)
+	if err != nil {
+		return err
+	}
+	if option == "1" {
+		return execNodeList(nil, nil)
+	}
+
+	nodes, err := c.GetNodes(ctx)
+	if err != nil {
+		return err
+	}
+	if len(nodes) == 0 {
+		fmt.Println("No resources found.")
+		return nil
+	}
+	nodeOptions := make([]huh.Option[string], 0, len(nodes))
+	for _, node := range nodes {
+		nodeOptions = append(nodeOptions, huh.NewOption(fmt.Sprintf("%s (%s)", node.Name, node.Status), node.Name))
+	}
+	nodeName, err := pick(fmt.Sprintf("Select a node (context: %s)", currentContext), nodeOptions, "", "")
+	if err != nil {
+		return err
+	}
+	switch option {
+	case "2":
+		return execNodeDescribe(nil, []string{nodeName})
+	case "3":
+		return execNodeCordon(nil, []string{nodeName})
+	case "4":
+		return execNodeUncordon(nil, []string{nodeName})
+	case "5":
+		var flags []string
+		form = huh.NewForm(
+			huh.NewGroup(
+				huh.NewMultiSelect[string]().
+					Title(fmt.Sprintf("Drain options (node: %s)", nodeName)).
+					Options(
+						huh.NewOption("Ignore DaemonSet-managed pods", "ignore-daemonsets").Selected(true),
+						huh.NewOption("Delete pods using emptyDir data", "delete-emptydir-data"),
+						huh.NewOption("Delete pods not managed by a controller", "force"),
+					).
+					Value(&flags),
+			),
+		)
+		err = runForm(form)
+		if err != nil {
+			return err
+		}
+		drainOptions.IgnoreDaemonSets = slices.Contains(flags, "ignore-daemonsets")
+		drainOptions.DeleteEmptyDirData = slices.Contains(flags, "delete-emptydir-data")
+		drainOptions.Force = slices.Contains(flags, "force")
+		return execNodeDrain(nil, []string{nodeName})
+	case "6":
+		return execNodeShell(nil, []string{nodeName})
+	}
+
+	return nil
+}
+
+func execNodeList(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	currentContext, err := resolveContext()
+	if err != nil {
+		return err
+	}
+	c, err := newClient(currentContext)
+	if err != nil {
+		return err
+	}
+	nodes, err := c.GetNodes(ctx)
+	if err != nil {
+		return err
+	}
+	if len(nodes) == 0 {
+		fmt.Println("No resources found.")
+		return nil
+	}
+	w := newTableWriter()
+	fmt.Fprintln(w, "NAME\tSTATUS\tROLES\tAGE\tVERSION\tCPU\tMEMORY\tPODS")
+	for _, node := range nodes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d/%d\n", node.Name, node.Status, node.Roles, formatAge(node.Created), node.Version, node.AllocatableCPU, node.AllocatableMemory, node.Pods, node.PodCapacity)
+	}
+
+	return w.Flush()
+}
+
+func execNodeDescribe(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	nodeName := args[0]
+	currentContext, err := resolveContext()
+	if err != nil {
+		return err
+	}
+	c, err := newClient(currentContext)
+	if err != nil {
+		return err
+	}
+	node, pods, err := c.GetNode(ctx, nodeName)
+	if err != nil {
+		return err
+	}
+
+	w := newTableWriter()
+	fmt.Fprintf(w, "Name:\t%s\n", node.Name)
+	fmt.Fprintf(w, "Status:\t%s\n", node.Status)
+	fmt.Fprintf(w, "Roles:\t%s\n", node.Roles)
+	fmt.Fprintf(w, "Internal IP:\t%s\n", valueOrNone(node.InternalIP))
+	fmt.Fprintf(w, "Kubelet version:\t%s\n", node.Version)
+	fmt.Fprintf(w, "OS image:\t%s\n", node.OSImage)
+	fmt.Fprintf(w, "Kernel version:\t%s\n", node.KernelVersion)
+	fmt.Fprintf(w, "Container runtime:\t%s\n", node.ContainerRuntime)
+	fmt.Fprintf(w, "Allocatable:\tcpu %s, memory %s, pods %d\n", node.AllocatableCPU, node.AllocatableMemory, node.PodCapacity)
+	fmt.Fprintf(w, "Taints:\t%s\n", valueOrNone(strings.Join(node.Taints, ", ")))
+	fmt.Fprintf(w, "Age:\t%s\n", formatAge(node.Created))
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Println("Conditions:")
+	w = newTableWriter()
+	fmt.Fprintln(w, "  TYPE\tSTATUS\tREASON\tMESSAGE")
+	for _, condition := range node.Conditions {
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", condition.Type, condition.Status, condition.Reason, condition.Message)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Pods (%d):\n", len(pods))
+	for _, pod := range pods {
+		fmt.Printf("  %s\n", pod)
+	}
+
+	return nil
+}
+
+func execNodeCordon(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	nodeName := args[0]
+	currentContext, err := resolveContext()
+	if err != nil {
+		return err
+	}
+	c, err := newClient(currentContext)
+	if err != nil {
+		return err
+	}
+	if err := confirmProtectedContext(currentContext, "cordon node "+nodeName); err != nil {
+		return err
+	}
+	err = c.SetNodeSchedulable(ctx, nodeName, false)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("node/%s cordoned\n", nodeName)
+
+	return nil
+}
+
+func execNodeUncordon(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	nodeName := args[0]
+	currentContext, err := resolveContext()
+	if err != nil {
+		return err
+	}
+	c, err := newClient(currentContext)
+	if err != nil {
+		return err
+	}
+	if err := confirmProtectedContext(currentContext, "uncordon node "+nodeName); err != nil {
+		return err
+	}
+	err = c.SetNodeSchedulable(ctx, nodeName, true)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("node/%s uncordoned\n", nodeName)
+
+	return nil
+}
+
+func execNodeDrain(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	nodeName := args[0]
+	currentContext, err := resolveContext()
+	if err != nil {
+		return err
+	}
+	c, err := newClient(currentContext)
+	if err != nil {
+		return err
+	}
+	// Evictions are checked in all namespaces, because the pods on the node can be in any namespace.
+	if err := checkAccess(ctx, c, currentContext, "", accessPatchNode, accessEvict); err != nil {
+		return err
+	}
+	if err := confirmProtectedContext(currentContext, "drain node "+nodeName); err != nil {
+		return err
+	}
+	start := time.Now()
+	err = c.DrainNode(ctx, nodeName, drainOptions, func(msg string) {
+		fmt.Printf("[%5s] %s\n", time.Since(start).Round(time.Second), msg)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func execNodeShell(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	nodeName := args[0]
+	c, currentContext, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	if err := checkAccess(ctx, c, currentContext, currentNamespace, accessCreatePod, accessExec); err != nil {
+		return err
+	}
+	if err := confirmProtectedContext(currentContext, "open a privileged shell on node "+nodeName); err != nil {
+		return err
+	}
+	podName, containerName, err := c.CreateNodeShellPod(ctx, currentNamespace, nodeName, nodeShellImage)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created pod %s on node %s.\n", podName, nodeName)
+
+	// Delete the pod when the session ends. A signal, also a SIGHUP when the terminal is closed, cancels ctx instead of
+	// terminating wimkube, so the session returns, the terminal is restored and the pod is still deleted.
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer stop()
+	defer func() {
+		if err := c.DeletePod(context.WithoutCancel(ctx), currentNamespace, podName); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return
+		}
+		fmt.Printf("Pod %s deleted.\n", podName)
+	}()
+
+	fmt.Printf("Waiting for pod %s to start...\n", podName)
+	if err := c.WaitForContainerRunning(ctx, currentNamespace, podName, containerName, containerStartTimeout); err != nil {
+		return err
+	}
+	fmt.Println("The root filesystem of the node is also mounted at /host.")
+	err = c.ExecCommandInContainer(ctx, currentNamespace, podName, containerName, internal.NodeShellCommand)
+	if ctx.Err() != nil {
+		return fmt.Errorf("shell on node %s interrupted", nodeName)
+	}
+
+	return err
+}
+
+func init() {
+	rootCmd.AddCommand(nodeCmd)
+	nodeCmd.AddCommand(nodeListCmd)
+	nodeCmd.AddCommand(nodeDescribeCmd)
+	nodeCmd.AddCommand(nodeCordonCmd)
+	nodeCmd.AddCommand(nodeUncordonCmd)
+	nodeCmd.AddCommand(nodeDrainCmd)
+	nodeCmd.AddCommand(nodeShellCmd)
+	nodeDrainCmd.Flags().BoolVar(&drainOptions.IgnoreDaemonSets, "ignore-daemonsets", false, "Ignore DaemonSet-managed pods.")
+	nodeDrainCmd.Flags().BoolVar(&drainOptions.DeleteEmptyDirData, "delete-emptydir-data", false, "Evict pods using emptyDir volumes, even though their data will be deleted.")
+	nodeDrainCmd.Flags().BoolVar(&drainOptions.Force, "force", false, "Evict pods that are not managed by a controller.")
+	nodeDrainCmd.Flags().DurationVar(&drainOptions.Timeout, "timeout", 5*time.Minute, "Time to wait for all pods to be evicted, 0 means wait forever.")
+	nodeShellCmd.Flags().StringVar(&nodeShellImage, "image", "busybox", "Image of the shell pod, must provide nsenter and sh.")
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"sigs.k8s.io/yaml"
+)
+
+// Formats of the -o/--output flag. Commands accept the subset that makes sense for their output; the default output
+// is a human-readable table or report.
+const (
+	outputJSON = "json"
+	outputYAML = "yaml"
+	outputWide = "wide"
+	outputName = "name"
+)
+
+// addOutputFlag adds the -o/--output flag accepting the formats to a command.
+func addOutputFlag(cmd *cobra.Command, output *string, formats ...string) {
+	cmd.Flags().StringVarP(output, "output", "o", "", "Output format: "+strings.Join(formats, ", ")+".")
+}
+
+// checkOutput returns an error if the value of the -o/--output flag is set and is not one of the formats.
+func checkOutput(output string, formats ...string) error {
+	if output == "" || slices.Contains(formats, output) {
+		return nil
+	}
+	valid := formats[len(formats)-1]
+	if len(formats) > 1 {
+		valid = strings.Join(formats[:len(formats)-1], ", ") + " or " + valid
+	}
+
+	return fmt.Errorf("invalid value for --output: %s (must be %s)", output, valid)
+}
+
+// printJSON writes v to stdout as indented JSON.
+func printJSON(v any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(v)
+}
+
+// printYAML writes v to stdout as YAML.
+func printYAML(v any) error {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(data)
+
+	return err
+}
+
+// newTableWriter returns a tabwriter on stdout that aligns tab-separated columns in the same way kubectl does.
+func newTableWriter() *tabwriter.Writer {
+	return newTableWriterTo(os.Stdout)
+}
+
+// newTableWriterTo returns a tabwriter on w that aligns tab-separated columns in the same way kubectl does.
+func newTableWriterTo(w io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+}
+
+// formatAge returns the time elapsed since t in a short human-readable form (e.g. 5m, 3d).
+func formatAge(t time.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(time.Since(t))
+}
+
+// formatTimeAgo returns the time elapsed since t followed by "ago", or "<none>" if t is not set.
+func formatTimeAgo(t time.Time) string {
+	if t.IsZero() {
+		return "<none>"
+	}
+	return formatAge(t) + " ago"
+}
+
+// valueOrNone returns s, or "<none>" if s is empty.
+func valueOrNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
+
+// valueOrDash returns s, or "-" if s is empty, for table cells that are not set.
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// formatExpiry returns an expiry time together with the time left until it, or "<unknown>" if t is not set.
+func formatExpiry(t time.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	when := t.Local().Format("2006-01-02 15:04")
+	if time.Until(t) <= 0 {
+		return fmt.Sprintf("%s (expired %s ago)", when, duration.HumanDuration(time.Since(t)))
+	}
+	return fmt.Sprintf("%s (in %s)", when, duration.HumanDuration(time.Until(t)))
+}
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/huh/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/sahilm/fuzzy"
+)
+
+// pickerHeight is the maximum number of options shown at once by a picker.
+const pickerHeight = 10
+
+var (
+	pickerTitleStyle  = lipgloss.NewStyle().Bold(true)
+	pickerCursorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("212"))
+	pickerMatchStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("212")).Underline(true)
+	pickerHelpStyle   = lipgloss.NewStyle().Faint(true)
+)
+
+type pickerModel struct {
+	title   string
+	options []huh.Option[string]
+	input   textinput.Model
+	matches fuzzy.Matches
+	cursor  int
+	offset  int
+	height  int
+
+	chosen  string
+	done    bool
+	back    bool
+	aborted bool
+}
+
+// pick shows a picker in which the user can narrow down the options by typing a fuzzy filter.
+// The filter matches the values of the options, e.g. pod names, and not the details their labels add after the value,
+// like the usage of a pod, which would make digits match every option.
+// The option with value selected is highlighted initially and the filter is pre-filled with query.
+// It returns the value of the chosen option, the error of escape if the user presses Esc with an empty filter and
+// huh.ErrUserAborted if the user presses Ctrl+C.
+func pick(title string, options []huh.Option[string], selected, query string) (string, error) {
+	input := textinput.New()
+	input.Prompt = "/ "
+	input.Placeholder = "type to filter"
+	input.SetValue(query)
+	m := &pickerModel{
+		title:   title,
+		options: options,
+		input:   input,
+		height:  pickerHeight,
+	}
+	m.filter()
+	for i, match := range m.matches {
+		if options[match.Index].Value == selected {
+			m.cursor = i
+			m.scroll()
+			break
+		}
+	}
+	if _, err := tea.NewProgram(m).Run(); err != nil {
+		return "", fmt.Errorf("unable to run picker: %w", err)
+	}
+	switch {
+	case m.aborted:
+		return "", huh.ErrUserAborted
+	case m.back:
+		return "", escape()
+	}
+
+	return m.chosen, nil
+}
+
+// pickValue is a shorthand for pick with options whose label is equal to their value.
+func pickValue(title string, values []string, selected, query string) (string, error) {
+	return pick(title, huh.NewOptions(values...), selected, query)
+}
+
+func (m *pickerModel) Init() tea.Cmd {
+	return m.input.Focus()
+}
+
+func (m *pickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// Leave room for the title, the filter, the status line and the help line.
+		m.height = max(1, min(pickerHeight, msg.Height-4))
+		m.scroll()
+		return m, nil
+	case tea.KeyPressMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			m.aborted = true
+			return m, tea.Quit
+		case "esc":
+			if m.input.Value() != "" {
+				m.input.SetValue("")
+				m.filter()
+				return m, nil
+			}
+			m.back = true
+			return m, tea.Quit
+		case "enter":
+			if len(m.matches) == 0 {
+				return m, nil
+			}
+			m.chosen = m.options[m.matches[m.cursor].Index].Value
+			m.done = true
+			return m, tea.Quit
+		case "up", "ctrl+p", "ctrl+k":
+			m.move(-1)
+			return m, nil
+		case "down", "ctrl+n", "ctrl+j":
+			m.move(1)
+			return m, nil
+		case "pgup":
+			m.move(-m.height)
+			return m, nil
+		case "pgdown":
+			m.move(m.height)
+			return m, nil
+		}
+	}
+	value := m.input.Value()
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != value {
+		m.filter()
+	}
+
+	return m, cmd
+}
+
+func (m *pickerModel) View() tea.View {
+	if m.done || m.back || m.aborted {
+		// Leave the chosen option on the screen, in the same way a huh form does.
+		if m.done {
+			return tea.NewView(pickerTitleStyle.Render(m.title) + "\n> " + m.options[m.matches[m.cursor].Index].Key + "\n")
+		}
+		return tea.NewView("")
+	}
+
+	var sb strings.Builder
+	sb.WriteString(pickerTitleStyle.Render(m.title) + "\n")
+	sb.WriteString(m.input.View() + "\n")
+	end := min(m.offset+m.height, len(m.matches))
+	for i := m.offset; i < end; i++ {
+		match := m.matches[i]
+		label := highlightMatch(m.options[match.Index].Key, match.MatchedIndexes)
+		if i == m.cursor {
+			sb.WriteString(pickerCursorStyle.Render("> ") + label + "\n")
+		} else {
+			sb.WriteString("  " + label + "\n")
+		}
+	}
+	sb.WriteString(pickerHelpStyle.Render(fmt.Sprintf("%d/%d • ↑/↓ move • enter select • esc back", len(m.matches), len(m.options))))
+
+	return tea.NewView(sb.String())
+}
+
+// filter updates the matching options after the filter has changed.
+// Without a filter all options match in their original order, otherwise the best matches come first. The labels start
+// with the value, so the matched characters of the value are also the ones to highlight in the label.
+func (m *pickerModel) filter() {
+	query := m.input.Value()
+	if query == "" {
+		m.matches = make(fuzzy.Matches, len(m.options))
+		for i, option := range m.options {
+			m.matches[i] = fuzzy.Match{Str: option.Key, Index: i}
+		}
+	} else {
+		values := make([]string, len(m.options))
+		for i, option := range m.options {
+			values[i] = option.Value
+		}
+		m.matches = fuzzy.Find(query, values)
+	}
+	m.cursor = 0
+	m.offset = 0
+}
+
+// move moves the cursor by delta options and scrolls the list to keep the cursor visible.
+func (m *pickerModel) move(delta int) {
+	if len(m.matches) == 0 {
+		return
+	}
+	m.cursor = max(0, min(m.cursor+delta, len(m.matches)-1))
+	m.scroll()
+}
+
+// scroll adjusts the first visible option so the cursor is visible.
+func (m *pickerModel) scroll() {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.height {
+		m.offset = m.cursor - m.height + 1
+	}
+}
+
+// highlightMatch renders s with the characters at the given byte indexes highlighted.
+func highlightMatch(s string, indexes []int) string {
+	if len(indexes) == 0 {
+		return s
+	}
+	matched := make(map[int]bool, len(indexes))
+	for _, i := range indexes {
+		matched[i] = true
+	}
+	var sb strings.Builder
+	for i, r := range s {
+		if matched[i] {
+			sb.WriteString(pickerMatchStyle.Render(string(r)))
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"testing"
+
+	"charm.land/bubbles/v2/textinput"
+	"charm.land/huh/v2"
+)
+
+func TestPickerFilter(t *testing.T) {
+	options := []huh.Option[string]{
+		huh.NewOption("api-1 (cpu 12m, memory 64Mi)", "api-1"),
+		huh.NewOption("web-1 (cpu 3m, memory 32Mi)", "web-1"),
+	}
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "", want: []string{"api-1", "web-1"}},
+		{query: "web", want: []string{"web-1"}},
+		// The usage in the labels is not filtered on.
+		{query: "cpu", want: nil},
+		{query: "12", want: nil},
+	}
+	for _, tt := range tests {
+		m := &pickerModel{options: options, input: textinput.New(), height: pickerHeight}
+		m.input.SetValue(tt.query)
+		m.filter()
+		var got []string
+		for _, match := range m.matches {
+			got = append(got, options[match.Index].Value)
+		}
+		if len(got) != len(tt.want) || (len(got) > 0 && got[0] != tt.want[0]) {
+			t.Errorf("filter(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"slices"
 
 	"charm.land/huh/v2"
 	"github.com/spf13/cobra"
@@ -9,70 +11,71 @@ import (
 	"github.com/wim-vdw/wimkube/internal"
 )
 
+var (
+	podWatch bool
+	podUntil string
+)
+
 var podCmd = &cobra.Command{
 	Use:   "pod",
 	Short: "Manage pods.",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return showPodMenu()
+		return loopMenu(showPodMenu)
 	},
 }
 
 var podListCmd = &cobra.Command{
-	Use:   "list",
-	Short: "List all pods.",
-	RunE:  execPodList,
+	Use:         "list",
+	Short:       "List all pods.",
+	Annotations: map[string]string{fanoutAnnotation: "true"},
+	RunE:        execPodList,
 }
 
 var podContainerListCmd = &cobra.Command{
-	Use:   "list-containers [pod-name]",
+	Use:   "list-containers [pod-name|filter]",
 	Short: "List all containers of a pod.",
-	Args:  cobra.ExactArgs(1),
+	Args:  cobra.MaximumNArgs(1),
 	RunE:  execPodContainerList,
 }
 
 var podContainerExecCmd = &cobra.Command{
-	Use:   "exec [pod-name] [container-name]",
+	Use:   "exec [pod-name|filter] [container-name]",
 	Short: "Execute an interactive shell in a container of a pod.",
-	Args:  cobra.ExactArgs(2),
+	Args:  cobra.MaximumNArgs(2),
 	RunE:  execPodContainerExec,
 }
 
 var podContainerLogsCmd = &cobra.Command{
-	Use:   "logs [pod-name] [container-name]",
+	Use:   "logs [pod-name|filter] [container-name]",
 	Short: "Get the logs of a container of a pod.",
-	Args:  cobra.ExactArgs(2),
+	Args:  cobra.MaximumNArgs(2),
 	RunE:  execPodContainerLogs,
 }
 
 func showPodMenu() error {
+	ctx := commandContext(nil)
 	var option string
-	currentContext, err := kubeConfig.GetCurrentContext()
-	if err != nil {
-		return err
-	}
-	c, err := internal.NewClient(viper.GetString("kubeconfig"), currentContext)
-	if err != nil {
-		return err
-	}
-	currentNamespace, err := kubeConfig.GetCurrentNamespace()
+	c, _, currentNamespace, err := resolveTarget()
 	if err != nil {
 		return err
 	}
 	title := fmt.Sprintf("Select an option (namespace: %s)", currentNamespace)
+	options := menuOptions(ctx, c, currentNamespace, []menuAction{
+		{"List all pods", "1", nil},
+		{"List all containers of a pod", "2", nil},
+		{"Execute an interactive shell in a container of a pod", "3", &accessExec},
+		{"Get the logs of a container of a pod", "4", nil},
+		{"Debug a pod with an ephemeral container", "5", &accessDebug},
+	})
 	form := huh.NewForm(
 		huh.NewGroup(
 			huh.NewSelect[string]().
 				Title(title).
-				Options(
-					huh.NewOption("List all pods", "1"),
-					huh.NewOption("List all containers of a pod", "2"),
-					huh.NewOption("Execute an interactive shell in a container of a pod", "3"),
-					huh.NewOption("Get the logs of a container of a pod", "4"),
-				).
+				Options(options...).
 				Value(&option),
 		),
 	)
-	err = form.Run()
+	err = runMenu(form)
 	if err != nil {
 		return err
 	}
@@ -80,7 +83,7 @@ func showPodMenu() error {
 	case "1":
 		return execPodList(nil, nil)
 	case "2":
-		pods, err := c.GetPods(currentNamespace)
+		pods, err := c.GetPods(ctx, currentNamespace)
 		if err != nil {
 			return err
 		}
@@ -88,23 +91,13 @@ func showPodMenu() error {
 			fmt.Printf("No resources found in %s namespace.\n", currentNamespace)
 			return nil
 		}
-		title := fmt.Sprintf("Select a pod (namespace: %s)", currentNamespace)
-		var podName string
-		form := huh.NewForm(
-			huh.NewGroup(
-				huh.NewSelect[string]().
-					Title(title).
-					Options(huh.NewOptions(pods...)...).
-					Value(&podName),
-			),
-		)
-		err = form.Run()
+		podName, err := selectPod(ctx, currentNamespace, pods, "", c)
 		if err != nil {
 			return err
 		}
 		return execPodContainerList(nil, []string{podName})
 	case "3":
-		podName, containerName, err := selectPodAndContainer(currentNamespace, c)
+		podName, containerName, err := selectPodAndContainer(ctx, currentNamespace, "", c)
 		if err != nil {
 			return err
 		}
@@ -113,7 +106,7 @@ func showPodMenu() error {
 		}
 		return execPodContainerExec(nil, []string{podName, containerName})
 	case "4":
-		podName, containerName, err := selectPodAndContainer(currentNamespace, c)
+		podName, containerName, err := selectPodAndContainer(ctx, currentNamespace, "", c)
 		if err != nil {
 			return err
 		}
@@ -121,13 +114,17 @@ func showPodMenu() error {
 			return nil
 		}
 		return execPodContainerLogs(nil, []string{podName, containerName})
+	case "5":
+		return execPodDebug(nil, nil)
 	}
 
 	return nil
 }
 
-func selectPodAndContainer(currentNamespace string, c *internal.Client) (string, string, error) {
-	pods, err := c.GetPods(currentNamespace)
+// selectPodAndContainer lets the user pick a pod in the namespace and then one of its containers.
+// The pod picker is pre-filtered with query. It returns an empty pod name if the namespace has no pods.
+func selectPodAndContainer(ctx context.Context, currentNamespace, query string, c *internal.Client) (string, string, error) {
+	pods, err := c.GetPods(ctx, currentNamespace)
 	if err != nil {
 		return "", "", err
 	}
@@ -135,38 +132,25 @@ func selectPodAndContainer(currentNamespace string, c *internal.Client) (string,
 		fmt.Printf("No resources found in %s namespace.\n", currentNamespace)
 		return "", "", nil
 	}
-
-	var podName string
-	title := fmt.Sprintf("Select a pod (namespace: %s)", currentNamespace)
-	form := huh.NewForm(
-		huh.NewGroup(
-			huh.NewSelect[string]().
-				Title(title).
-				Options(huh.NewOptions(pods...)...).
-				Value(&podName),
-		),
-	)
-	err = form.Run()
+	podName, err := selectPod(ctx, currentNamespace, pods, query, c)
 	if err != nil {
 		return "", "", err
 	}
-
-	containers, err := c.GetContainers(currentNamespace, podName)
+	containerName, err := selectContainer(ctx, currentNamespace, podName, c)
 	if err != nil {
 		return "", "", err
 	}
 
-	var containerName string
-	title = fmt.Sprintf("Select a container (namespace: %s, pod: %s)", currentNamespace, podName)
-	form = huh.NewForm(
-		huh.NewGroup(
-			huh.NewSelect[string]().
-				Title(title).
-				Options(huh.NewOptions(containers...)...).
-				Value(&containerName),
-		),
-	)
-	err = form.Run()
+	return podName, containerName, nil
+}
+
+// selectPodAndContainerFrom lets the user pick one of the given pods and then one of its containers.
+func selectPodAndContainerFrom(ctx context.Context, currentNamespace string, pods []string, c *internal.Client) (string, string, error) {
+	podName, err := selectPod(ctx, currentNamespace, pods, "", c)
+	if err != nil {
+		return "", "", err
+	}
+	containerName, err := selectContainer(ctx, currentNamespace, podName, c)
 	if err != nil {
 		return "", "", err
 	}
@@ -174,20 +158,87 @@ func selectPodAndContainer(currentNamespace string, c *internal.Client) (string,
 	return podName, containerName, nil
 }
 
-func execPodList(cmd *cobra.Command, args []string) error {
-	currentContext, err := kubeConfig.GetCurrentContext()
+// selectPod lets the user pick one of the given pods, with the picker pre-filtered with query.
+// If query is the exact name of one of the pods, that pod is returned without showing the picker.
+func selectPod(ctx context.Context, currentNamespace string, pods []string, query string, c *internal.Client) (string, error) {
+	if slices.Contains(pods, query) {
+		return query, nil
+	}
+	title := fmt.Sprintf("Select a pod (namespace: %s)", currentNamespace)
+
+	return pick(title, podOptions(ctx, currentNamespace, pods, c), "", query)
+}
+
+// selectContainer lets the user pick one of the containers of a pod.
+// If the pod has a single container, that container is returned without showing the picker.
+func selectContainer(ctx context.Context, currentNamespace, podName string, c *internal.Client) (string, error) {
+	containers, err := c.GetContainers(ctx, currentNamespace, podName)
 	if err != nil {
-		return err
+		return "", err
+	}
+	if len(containers) == 1 {
+		return containers[0], nil
+	}
+	title := fmt.Sprintf("Select a container (namespace: %s, pod: %s)", currentNamespace, podName)
+
+	return pickValue(title, containers, "", "")
+}
+
+// podAndContainerFromArgs returns the pod and container given as arguments.
+// If the pod is omitted or is not the exact name of a pod, a pod picker pre-filtered with it is shown.
+// If the container is omitted, a container picker is shown when the pod has more than one container.
+func podAndContainerFromArgs(ctx context.Context, currentNamespace string, args []string, c *internal.Client) (string, string, error) {
+	if len(args) == 2 {
+		return args[0], args[1], nil
+	}
+	var query string
+	if len(args) == 1 {
+		query = args[0]
 	}
-	c, err := internal.NewClient(viper.GetString("kubeconfig"), currentContext)
+
+	return selectPodAndContainer(ctx, currentNamespace, query, c)
+}
+
+// podOptions returns the picker options for the given pods.
+// If show-pod-usage is enabled in the configuration file, the current CPU and memory usage is added to each pod
+// when the metrics API is available.
+func podOptions(ctx context.Context, currentNamespace string, pods []string, c *internal.Client) []huh.Option[string] {
+	if !viper.GetBool("show-pod-usage") {
+		return huh.NewOptions(pods...)
+	}
+	usage, err := c.GetPodUsage(ctx, currentNamespace, "")
 	if err != nil {
-		return err
+		return huh.NewOptions(pods...)
+	}
+	labels := make(map[string]string, len(usage))
+	for _, pod := range usage {
+		labels[pod.Name] = fmt.Sprintf("%s (cpu %s, memory %s)", pod.Name, formatCPU(pod.CPU), formatMemory(pod.Memory))
 	}
-	currentNamespace, err := kubeConfig.GetCurrentNamespace()
+	options := make([]huh.Option[string], 0, len(pods))
+	for _, podName := range pods {
+		label, exists := labels[podName]
+		if !exists {
+			label = podName
+		}
+		options = append(options, huh.NewOption(label, podName))
+	}
+
+	return options
+}
+
+func execPodList(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	if isFanout() {
+		return execPodListFanout(ctx)
+	}
+	c, _, currentNamespace, err := resolveTarget()
 	if err != nil {
 		return err
 	}
-	pods, err := c.GetPods(currentNamespace)
+	if podWatch || podUntil != "" {
+		return watchPods(ctx, c, currentNamespace, podUntil)
+	}
+	pods, err := c.GetPods(ctx, currentNamespace)
 	if err != nil {
 		return err
 	}
@@ -202,21 +253,59 @@ func execPodList(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func execPodListFanout(ctx context.Context) error {
+	if podWatch || podUntil != "" {
+		return fmt.Errorf("--watch and --until cannot be used with --context or --all-contexts")
+	}
+	contexts, err := selectedContexts()
+	if err != nil {
+		return err
+	}
+	results := fanOut(contexts, func(contextName string) ([]internal.PodSummary, error) {
+		c, namespace, err := contextClient(contextName)
+		if err != nil {
+			return nil, err
+		}
+		return c.GetPodSummaries(ctx, namespace)
+	})
+
+	w := newTableWriter()
+	fmt.Fprintln(w, "CONTEXT\tNAMESPACE\tNAME\tREADY\tSTATUS\tRESTARTS\tAGE")
+	for _, result := range results {
+		for _, pod := range result.Value {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n", result.Context, pod.Namespace, pod.Name, pod.Ready, pod.Status, pod.Restarts, formatAge(pod.Created))
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	return reportFanoutErrors(results)
+}
+
 func execPodContainerList(cmd *cobra.Command, args []string) error {
-	podName := args[0]
-	currentContext, err := kubeConfig.GetCurrentContext()
+	ctx := commandContext(cmd)
+	c, _, currentNamespace, err := resolveTarget()
 	if err != nil {
 		return err
 	}
-	c, err := internal.NewClient(viper.GetString("kubeconfig"), currentContext)
+	var podName string
+	if len(args) == 1 {
+		podName = args[0]
+	}
+	pods, err := c.GetPods(ctx, currentNamespace)
 	if err != nil {
 		return err
 	}
-	currentNamespace, err := kubeConfig.GetCurrentNamespace()
+	if len(pods) == 0 {
+		fmt.Printf("No resources found in %s namespace.\n", currentNamespace)
+		return nil
+	}
+	podName, err = selectPod(ctx, currentNamespace, pods, podName, c)
 	if err != nil {
 		return err
 	}
-	containers, err := c.GetContainers(currentNamespace, podName)
+	containers, err := c.GetContainers(ctx, currentNamespace, podName)
 	if err != nil {
 		return err
 	}
@@ -228,21 +317,22 @@ func execPodContainerList(cmd *cobra.Command, args []string) error {
 }
 
 func execPodContainerExec(cmd *cobra.Command, args []string) error {
-	podName := args[0]
-	containerName := args[1]
-	currentContext, err := kubeConfig.GetCurrentContext()
+	ctx := commandContext(cmd)
+	c, currentContext, currentNamespace, err := resolveTarget()
 	if err != nil {
 		return err
 	}
-	c, err := internal.NewClient(viper.GetString("kubeconfig"), currentContext)
-	if err != nil {
+	if err := checkAccess(ctx, c, currentContext, currentNamespace, accessExec); err != nil {
 		return err
 	}
-	currentNamespace, err := kubeConfig.GetCurrentNamespace()
+	podName, containerName, err := podAndContainerFromArgs(ctx, currentNamespace, args, c)
 	if err != nil {
 		return err
 	}
-	err = c.ExecInContainer(currentNamespace, podName, containerName)
+	if podName == "" {
+		return nil
+	}
+	err = c.ExecInContainer(ctx, currentNamespace, podName, containerName)
 	if err != nil {
 		return err
 	}
@@ -251,21 +341,19 @@ func execPodContainerExec(cmd *cobra.Command, args []string) error {
 }
 
 func execPodContainerLogs(cmd *cobra.Command, args []string) error {
-	podName := args[0]
-	containerName := args[1]
-	currentContext, err := kubeConfig.GetCurrentContext()
+	ctx := commandContext(cmd)
+	c, _, currentNamespace, err := resolveTarget()
 	if err != nil {
 		return err
 	}
-	c, err := internal.NewClient(viper.GetString("kubeconfig"), currentContext)
+	podName, containerName, err := podAndContainerFromArgs(ctx, currentNamespace, args, c)
 	if err != nil {
 		return err
 	}
-	currentNamespace, err := kubeConfig.GetCurrentNamespace()
-	if err != nil {
-		return err
+	if podName == "" {
+		return nil
 	}
-	logs, err := c.GetPodLogs(currentNamespace, podName, containerName)
+	logs, err := c.GetPodLogs(ctx, currentNamespace, podName, containerName)
 	if err != nil {
 		return err
 	}
@@ -280,4 +368,6 @@ func init() {
 	podCmd.AddCommand(podContainerListCmd)
 	podCmd.AddCommand(podContainerExecCmd)
 	podCmd.AddCommand(podContainerLogsCmd)
+	podListCmd.Flags().BoolVarP(&podWatch, "watch", "w", false, "Watch the pods and show changes as they happen.")
+	podListCmd.Flags().StringVar(&podUntil, "until", "", "Stop watching when a condition is met: ready (all pods ready) or empty (no pods left). Implies --watch.")
 }
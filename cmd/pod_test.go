@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func runningPod(namespace, name string, containers ...string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         namespace,
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-5 * time.Minute)),
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	for _, container := range containers {
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: container})
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+			Name:  container,
+			Ready: true,
+			State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+		})
+	}
+
+	return pod
+}
+
+func TestPodCommands(t *testing.T) {
+	objects := map[string][]runtime.Object{
+		"dev": {
+			runningPod("default", "web-1", "web"),
+			runningPod("default", "web-2", "web"),
+			runningPod("kube-system", "coredns-1", "coredns"),
+		},
+		"prod": {
+			runningPod("payments", "api-1", "api", "envoy"),
+		},
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "list pods of the current namespace",
+			args: []string{"pod", "list"},
+			want: "web-1\nweb-2\n",
+		},
+		{
+			name: "list pods of another namespace",
+			args: []string{"pod", "list", "-n", "kube-system"},
+			want: "coredns-1\n",
+		},
+		{
+			name: "list pods of an empty namespace",
+			args: []string{"pod", "list", "-n", "orders"},
+			want: "No resources found in orders namespace.\n",
+		},
+		{
+			name: "list pods of all contexts",
+			args: []string{"pod", "list", "--all-contexts"},
+			want: "CONTEXT   NAMESPACE   NAME    READY   STATUS    RESTARTS   AGE\n" +
+				"dev       default     web-1   1/1     Running   0          5m\n" +
+				"dev       default     web-2   1/1     Running   0          5m\n" +
+				"prod      payments    api-1   2/2     Running   0          5m\n",
+		},
+		{
+			name: "list containers of a pod",
+			args: []string{"pod", "list-containers", "-c", "prod", "api-1"},
+			want: "api\nenvoy\n",
+		},
+		{
+			name:    "watch is not supported with several contexts",
+			args:    []string{"pod", "list", "--all-contexts", "--watch"},
+			wantErr: "--watch and --until cannot be used with --context or --all-contexts",
+		},
+		{
+			name:    "exec does not support several contexts",
+			args:    []string{"pod", "exec", "--context", "dev,prod", "web-1"},
+			wantErr: "multiple contexts are not supported by wimkube pod exec",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t, objects)
+			got, err := env.run(t, tt.args...)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,345 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"charm.land/huh/v2"
+	"github.com/spf13/cobra"
+	"github.com/wim-vdw/wimkube/internal"
+)
+
+var (
+	getOutput             string
+	getAllNamespaces      bool
+	getSelector           string
+	resourceDeleteConfirm string
+)
+
+var resourceCmd = &cobra.Command{
+	Use:   "resource",
+	Short: "Get, describe, edit, delete and apply resources of any kind.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return loopMenu(showResourceMenu)
+	},
+}
+
+var getCmd = &cobra.Command{
+	Use:   "get <kind> [name]",
+	Short: "Get resources of any kind, including custom resources, with the columns of kubectl get.",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE:  execGet,
+}
+
+var describeCmd = &cobra.Command{
+	Use:   "describe <kind> [name]",
+	Short: "Describe a resource of any kind, like kubectl describe.",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE:  execDescribe,
+}
+
+var deleteCmd = &cobra.Command{
+	Use:   "delete <kind> [name]",
+	Short: "Delete a resource of any kind.",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE:  execDelete,
+}
+
+func showResourceMenu() error {
+	var option string
+	_, _, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title(fmt.Sprintf("Select an option (namespace: %s)", currentNamespace)).
+				Options(
+					huh.NewOption("Get resources of a kind", "1"),
+					huh.NewOption("Describe a resource", "2"),
+					huh.NewOption("Edit a resource", "3"),
+					huh.NewOption("Delete a resource", "4"),
+					huh.NewOption("Apply manifests", "5"),
+				).
+				Value(&option),
+		),
+	)
+	err = runMenu(form)
+	if err != nil {
+		return err
+	}
+	switch option {
+	case "1":
+		return execGet(nil, nil)
+	case "2":
+		return execDescribe(nil, nil)
+	case "3":
+		return execEdit(nil, nil)
+	case "4":
+		return execDelete(nil, nil)
+	case "5":
+		form := huh.NewForm(
+			huh.NewGroup(
+				huh.NewInput().
+					Title("Enter a manifest file or directory").
+					Validate(huh.ValidateNotEmpty()).
+					Value(&applyFilename),
+			),
+		)
+		if err := runForm(form); err != nil {
+			return err
+		}
+		defer func() { applyFilename = "" }()
+		return execApply(nil, nil)
+	}
+
+	return nil
+}
+
+// resourceArgs returns the kind and the name given as arguments, either as "<kind> [name]" or as "<kind>/<name>".
+// Without arguments, e.g. in the menu, the kind is picked from the resource types of the cluster.
+func resourceArgs(ctx context.Context, c *internal.Client, args []string) (string, string, error) {
+	switch len(args) {
+	case 0:
+		resources, err := c.ListResourceTypes(ctx)
+		if err != nil {
+			return "", "", err
+		}
+		names := make([]string, 0, len(resources))
+		for _, resource := range resources {
+			names = append(names, resource.String())
+		}
+		kind, err := pickValue("Select a kind", names, "", "")
+		return kind, "", err
+	case 1:
+		kind, name, _ := strings.Cut(args[0], "/")
+		return kind, name, nil
+	}
+
+	return args[0], args[1], nil
+}
+
+// pickObject lets the user pick an object of a resource type in the namespace.
+func pickObject(ctx context.Context, c *internal.Client, resource internal.APIResource, namespace string) (string, error) {
+	table, err := c.GetTable(ctx, resource, namespace, "", "")
+	if err != nil {
+		return "", err
+	}
+	if len(table.Rows) == 0 {
+		if resource.Namespaced {
+			return "", fmt.Errorf("no %s found in %s namespace", resource, namespace)
+		}
+		return "", fmt.Errorf("no %s found", resource)
+	}
+	names := make([]string, 0, len(table.Rows))
+	for _, row := range table.Rows {
+		names = append(names, row.Name)
+	}
+
+	return pickValue(fmt.Sprintf("Select %s", resource), names, "", "")
+}
+
+func execGet(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	if err := checkOutput(getOutput, outputJSON, outputYAML, outputWide, outputName); err != nil {
+		return err
+	}
+	c, _, namespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	kind, name, err := resourceArgs(ctx, c, args)
+	if err != nil {
+		return err
+	}
+	resource, err := c.FindResource(ctx, kind)
+	if err != nil {
+		return err
+	}
+	if getAllNamespaces {
+		if name != "" {
+			return fmt.Errorf("a resource cannot be retrieved by name across all namespaces")
+		}
+		namespace = ""
+	}
+
+	if getOutput == outputJSON || getOutput == outputYAML {
+		objects, err := c.GetObjects(ctx, resource, namespace, name, getSelector)
+		if err != nil {
+			return err
+		}
+		var v any = map[string]any{"apiVersion": "v1", "kind": "List", "items": objects}
+		if name != "" {
+			v = objects[0].Object
+		}
+		if getOutput == outputYAML {
+			return printYAML(v)
+		}
+		return printJSON(v)
+	}
+
+	table, err := c.GetTable(ctx, resource, namespace, name, getSelector)
+	if err != nil {
+		return err
+	}
+	if len(table.Rows) == 0 {
+		if resource.Namespaced && namespace != "" {
+			fmt.Printf("No resources found in %s namespace.\n", namespace)
+		} else {
+			fmt.Println("No resources found")
+		}
+		return nil
+	}
+	if getOutput == outputName {
+		for _, row := range table.Rows {
+			fmt.Println(resource.ObjectName(row.Name))
+		}
+		return nil
+	}
+
+	return printTable(table, resource.Namespaced && namespace == "", getOutput == outputWide)
+}
+
+// printTable prints a server-side table the way kubectl get does, with a NAMESPACE column if it lists the objects of
+// all namespaces. Columns with a priority are only printed in the wide output.
+func printTable(table *internal.Table, showNamespace, wide bool) error {
+	w := newTableWriter()
+	var header []string
+	if showNamespace {
+		header = append(header, "NAMESPACE")
+	}
+	for _, column := range table.Columns {
+		if wide || column.Priority == 0 {
+			header = append(header, column.Name)
+		}
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range table.Rows {
+		var cells []string
+		if showNamespace {
+			cells = append(cells, row.Namespace)
+		}
+		for i, column := range table.Columns {
+			if (wide || column.Priority == 0) && i < len(row.Cells) {
+				cells = append(cells, row.Cells[i])
+			}
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+
+	return w.Flush()
+}
+
+func execDescribe(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	c, _, namespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	kind, name, err := resourceArgs(ctx, c, args)
+	if err != nil {
+		return err
+	}
+	resource, err := c.FindResource(ctx, kind)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		name, err = pickObject(ctx, c, resource, namespace)
+		if err != nil {
+			return err
+		}
+	}
+	output, err := c.DescribeObject(ctx, resource, namespace, name)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+
+	return nil
+}
+
+func execDelete(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	c, currentContext, namespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	kind, name, err := resourceArgs(ctx, c, args)
+	if err != nil {
+		return err
+	}
+	resource, err := c.FindResource(ctx, kind)
+	if err != nil {
+		return err
+	}
+	if resource.Group == "" && resource.Resource == "namespaces" {
+		return deleteNamespace(cmd, name)
+	}
+	if name == "" {
+		name, err = pickObject(ctx, c, resource, namespace)
+		if err != nil {
+			return err
+		}
+	}
+	access := internal.Access{Verb: "delete", Group: resource.Group, Resource: resource.Resource}
+	if !resource.Namespaced {
+		namespace = ""
+	}
+	if err := checkAccess(ctx, c, currentContext, namespace, access); err != nil {
+		return err
+	}
+
+	where := fmt.Sprintf("context %s", currentContext)
+	if resource.Namespaced {
+		where = fmt.Sprintf("namespace %s of context %s", namespace, currentContext)
+	}
+	switch resourceDeleteConfirm {
+	case "":
+		confirmed, err := confirm(fmt.Sprintf("Delete %s from %s?", resource.ObjectName(name), where))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return errAborted
+		}
+	case name:
+	default:
+		return fmt.Errorf("--confirm %s does not match %s", resourceDeleteConfirm, name)
+	}
+	if err := confirmProtectedContext(currentContext, "delete "+resource.ObjectName(name)); err != nil {
+		return err
+	}
+	if err := c.DeleteObject(ctx, resource, namespace, name); err != nil {
+		return err
+	}
+	fmt.Printf("%s deleted from %s.\n", resource.ObjectName(name), where)
+
+	return nil
+}
+
+// deleteNamespace deletes a namespace with the safeguards of namespace delete, as a namespace is deleted with
+// everything in it: namespaces the cluster needs cannot be deleted, the contents are shown and the name must be typed.
+func deleteNamespace(cmd *cobra.Command, name string) error {
+	var args []string
+	if name != "" {
+		args = []string{name}
+	}
+	namespaceDeleteConfirm = resourceDeleteConfirm
+	defer func() { namespaceDeleteConfirm = "" }()
+
+	return execNamespaceDelete(cmd, args)
+}
+
+func init() {
+	rootCmd.AddCommand(resourceCmd)
+	rootCmd.AddCommand(getCmd)
+	rootCmd.AddCommand(describeCmd)
+	rootCmd.AddCommand(deleteCmd)
+	addOutputFlag(getCmd, &getOutput, outputJSON, outputYAML, outputWide, outputName)
+	getCmd.Flags().BoolVarP(&getAllNamespaces, "all-namespaces", "A", false, "List the resources in all namespaces.")
+	getCmd.Flags().StringVarP(&getSelector, "selector", "l", "", "Label selector to filter the resources, e.g. app=api.")
+	deleteCmd.Flags().StringVar(&resourceDeleteConfirm, "confirm", "", "Name of the resource, to confirm the deletion without a prompt, e.g. in scripts.")
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// testResources are the resource types the fake discovery serves, including a custom resource.
+var testResources = []*metav1.APIResourceList{
+	{GroupVersion: "v1", APIResources: []metav1.APIResource{
+		{Name: "pods", SingularName: "pod", Kind: "Pod", Namespaced: true, ShortNames: []string{"po"}, Verbs: []string{"get", "list", "delete"}},
+		{Name: "configmaps", SingularName: "configmap", Kind: "ConfigMap", Namespaced: true, ShortNames: []string{"cm"}, Verbs: []string{"get", "list", "delete", "patch"}},
+		{Name: "namespaces", SingularName: "namespace", Kind: "Namespace", ShortNames: []string{"ns"}, Verbs: []string{"get", "list", "delete"}},
+		{Name: "nodes", SingularName: "node", Kind: "Node", ShortNames: []string{"no"}, Verbs: []string{"get", "list", "delete"}},
+	}},
+	{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{
+		{Name: "deployments", SingularName: "deployment", Kind: "Deployment", Namespaced: true, ShortNames: []string{"deploy"}, Verbs: []string{"get", "list", "delete"}},
+	}},
+	{GroupVersion: "example.com/v1", APIResources: []metav1.APIResource{
+		{Name: "widgets", SingularName: "widget", Kind: "Widget", Namespaced: true, ShortNames: []string{"wd"}, Verbs: []string{"get", "list", "delete"}},
+	}},
+}
+
+func TestGet(t *testing.T) {
+	widget := &unstructured.Unstructured{}
+	widget.SetGroupVersionKind(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"})
+	widget.SetNamespace("default")
+	widget.SetName("blue")
+	env := newTestEnv(t, map[string][]runtime.Object{"dev": {
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "api-1"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web-1", Labels: map[string]string{"app": "web"}}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "payments", Name: "api"}},
+		widget,
+	}})
+	env.clientsets["dev"].Resources = testResources
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "list by short name",
+			args: []string{"get", "po"},
+			want: "NAME    AGE\napi-1   <unknown>\nweb-1   <unknown>\n",
+		},
+		{
+			name: "list names by kind with a label selector",
+			args: []string{"get", "Pod", "-l", "app=web", "-o", "name"},
+			want: "pod/web-1\n",
+		},
+		{
+			name: "list in all namespaces",
+			args: []string{"get", "deployments.apps", "-A"},
+			want: "NAMESPACE   NAME   AGE\npayments    api    <unknown>\n",
+		},
+		{
+			name: "get a custom resource by kind/name",
+			args: []string{"get", "widget/blue", "-o", "name"},
+			want: "widget.example.com/blue\n",
+		},
+		{
+			name: "no resources in the namespace",
+			args: []string{"get", "deploy"},
+			want: "No resources found in default namespace.\n",
+		},
+		{
+			name:    "unknown resource type",
+			args:    []string{"get", "gizmos"},
+			wantErr: "the server doesn't have a resource type gizmos",
+		},
+		{
+			name:    "missing object",
+			args:    []string{"get", "pods", "db-1"},
+			wantErr: "unable to get pods db-1",
+		},
+		{
+			name:    "invalid output format",
+			args:    []string{"get", "pods", "-o", "table"},
+			wantErr: "invalid value for --output: table (must be json, yaml, wide or name)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := env.run(t, tt.args...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	got, err := env.run(t, "get", "pods", "api-1", "-o", "yaml")
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	if !strings.Contains(got, "kind: Pod\n") || !strings.Contains(got, "  name: api-1\n") {
+		t.Errorf("output of -o yaml = %q, want the pod api-1", got)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	env := newTestEnv(t, map[string][]runtime.Object{"dev": {
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "api"}},
+	}})
+	env.clientsets["dev"].Resources = testResources
+	deployments := env.dynamics["dev"].Resource(schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}).Namespace("default")
+
+	if _, err := env.run(t, "delete", "deploy", "api", "--confirm", "web"); err == nil || !strings.Contains(err.Error(), "--confirm web does not match api") {
+		t.Errorf("error = %v, want a mismatch of --confirm", err)
+	}
+	if _, err := deployments.Get(t.Context(), "api", metav1.GetOptions{}); err != nil {
+		t.Fatalf("deployment api was deleted without confirmation: %v", err)
+	}
+
+	got, err := env.run(t, "delete", "deploy", "api", "--confirm", "api")
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	if want := "deployment.apps/api deleted from namespace default of context dev.\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+	if _, err := deployments.Get(t.Context(), "api", metav1.GetOptions{}); err == nil {
+		t.Errorf("deployment api still exists")
+	}
+}
+
+func TestDeleteNamespace(t *testing.T) {
+	env := newTestEnv(t, map[string][]runtime.Object{"dev": namespaces("default", "kube-system", "payments")})
+	env.clientsets["dev"].Resources = testResources
+
+	if _, err := env.run(t, "delete", "ns", "kube-system", "--confirm", "kube-system"); err == nil || !strings.Contains(err.Error(), "namespace kube-system is needed by the cluster") {
+		t.Errorf("error = %v, want kube-system to be refused", err)
+	}
+	got, err := env.run(t, "delete", "namespace/payments", "--confirm", "payments")
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	if !strings.Contains(got, "Namespace payments is being deleted.\n") {
+		t.Errorf("output = %q, want the namespace to be deleted like namespace delete does", got)
+	}
+	if _, err := env.clientsets["dev"].CoreV1().Namespaces().Get(t.Context(), "payments", metav1.GetOptions{}); err == nil {
+		t.Errorf("namespace payments still exists")
+	}
+}
@@ -1,13 +1,18 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/wim-vdw/wimkube/internal"
+	"golang.org/x/term"
 )
 
 var kubeConfig *internal.KubeConfig
@@ -19,25 +24,65 @@ var rootCmd = &cobra.Command{
 		if !cmd.HasParent() {
 			return nil
 		}
-		if viper.GetString("kubeconfig") == "" {
-			homeDir, err := os.UserHomeDir()
-			if err != nil {
-				return fmt.Errorf("could not determine home directory: %w", err)
-			}
-			viper.Set("kubeconfig", filepath.Join(homeDir, ".kube", "config"))
-		}
-		k, err := internal.NewKubeConfig(viper.GetString("kubeconfig"))
-		if err != nil {
+		if err := checkFanout(cmd); err != nil {
 			return err
 		}
-		kubeConfig = k
-		return nil
+		return initKubeConfig()
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return cmd.Help()
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			return cmd.Help()
+		}
+		if err := checkFanout(cmd); err != nil {
+			return err
+		}
+		if err := initKubeConfig(); err != nil {
+			return err
+		}
+		return showMainMenu()
 	},
 }
 
+// initKubeConfig loads the kubeconfig and the optional wimkube configuration file.
+// If no kubeconfig is specified, the default kubeconfig (~/.kube/config) is used.
+func initKubeConfig() error {
+	if viper.GetString("kubeconfig") == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("could not determine home directory: %w", err)
+		}
+		viper.Set("kubeconfig", filepath.Join(homeDir, ".kube", "config"))
+	}
+	k, err := internal.NewKubeConfig(viper.GetString("kubeconfig"))
+	if err != nil {
+		return err
+	}
+	kubeConfig = k
+
+	return loadConfig()
+}
+
+// loadConfig reads the optional wimkube configuration file (~/.wimkube.yaml).
+// A missing configuration file is not an error.
+func loadConfig() error {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("could not determine home directory: %w", err)
+	}
+	viper.SetConfigName(".wimkube")
+	viper.SetConfigType("yaml")
+	viper.AddConfigPath(homeDir)
+	if err := viper.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if errors.As(err, &notFound) {
+			return nil
+		}
+		return fmt.Errorf("could not read config file: %w", err)
+	}
+
+	return nil
+}
+
 func SetVersion(version string) {
 	rootCmd.Version = version
 }
@@ -55,16 +100,40 @@ func SetBuildTime(bt string) {
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		// After the first signal has cancelled the running operation, a second one terminates wimkube immediately.
+		<-ctx.Done()
+		stop()
+	}()
+	rootContext = ctx
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		os.Exit(1)
 	}
 }
 
+// rootContext is the context wimkube is executed with, which is cancelled on SIGINT or SIGTERM.
+var rootContext = context.Background()
+
+// commandContext returns the context of the running command.
+// The menus call the exec functions without a command, so they get the context wimkube is executed with.
+func commandContext(cmd *cobra.Command) context.Context {
+	if cmd != nil && cmd.Context() != nil {
+		return cmd.Context()
+	}
+
+	return rootContext
+}
+
 func init() {
 	rootCmd.PersistentFlags().BoolP("help", "h", false, "Display this help message.")
 	rootCmd.Flags().BoolP("version", "v", false, "Display version information.")
 	rootCmd.PersistentFlags().StringP("kubeconfig", "", "", "Path to the kubeconfig file to use. If not specified, the default kubeconfig will be used.")
 	rootCmd.PersistentFlags().IntP("request-timeout", "t", 30, "Timeout in seconds for Kubernetes API requests.")
+	rootCmd.PersistentFlags().StringSliceVarP(&contextPatterns, "context", "c", nil, "Context to use for this command only. Commands that support multiple contexts also accept a comma-separated list of names or glob patterns (e.g. 'prod-*').")
+	rootCmd.PersistentFlags().StringVarP(&namespaceOverride, "namespace", "n", "", "Namespace to use for this command only.")
+	rootCmd.PersistentFlags().BoolVar(&allContexts, "all-contexts", false, "Query all contexts in the kubeconfig.")
 	rootCmd.SetVersionTemplate("wimkube version: {{ .Version }}\n")
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
 	rootCmd.SetHelpCommand(&cobra.Command{Hidden: true})
@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"fmt"
+
+	"charm.land/huh/v2"
+	"github.com/spf13/cobra"
+	"github.com/wim-vdw/wimkube/internal"
+)
+
+var secretReveal bool
+
+var secretCmd = &cobra.Command{
+	Use:   "secret",
+	Short: "Manage secrets.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return loopMenu(showSecretMenu)
+	},
+}
+
+var secretListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all secrets.",
+	RunE:  execSecretList,
+}
+
+var secretKeysCmd = &cobra.Command{
+	Use:   "keys [secret-name]",
+	Short: "List the keys of a secret and the size of their values.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  execSecretKeys,
+}
+
+var secretGetCmd = &cobra.Command{
+	Use:   "get [secret-name] [key]",
+	Short: "Get the value of a key of a secret (masked unless --reveal is set).",
+	Args:  cobra.ExactArgs(2),
+	RunE:  execSecretGet,
+}
+
+var secretEditCmd = &cobra.Command{
+	Use:   "edit [secret-name] [key]",
+	Short: "Edit the decoded value of a key of a secret in $EDITOR.",
+	Args:  cobra.ExactArgs(2),
+	RunE:  execSecretEdit,
+}
+
+func showSecretMenu() error {
+	ctx := commandContext(nil)
+	var option string
+	c, _, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("Select an option (namespace: %s)", currentNamespace)
+	options := menuOptions(ctx, c, currentNamespace, []menuAction{
+		{"List all secrets", "1", nil},
+		{"List the keys of a secret", "2", &accessGetSecret},
+		{"Get the masked value of a key of a secret", "3", &accessGetSecret},
+		{"Reveal the decoded value of a key of a secret", "4", &accessGetSecret},
+		{"Edit the value of a key of a secret", "5", &accessPatchSecret},
+	})
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title(title).
+				Options(options...).
+				Value(&option),
+		),
+	)
+	err = runMenu(form)
+	if err != nil {
+		return err
+	}
+	if option == "1" {
+		return execSecretList(nil, nil)
+	}
+
+	secrets, err := c.GetSecrets(ctx, currentNamespace)
+	if err != nil {
+		return err
+	}
+	if len(secrets) == 0 {
+		fmt.Printf("No resources found in %s namespace.\n", currentNamespace)
+		return nil
+	}
+	secretNames := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		secretNames = append(secretNames, secret.Name)
+	}
+	title = fmt.Sprintf("Select a secret (namespace: %s)", currentNamespace)
+	secretName, err := pickValue(title, secretNames, "", "")
+	if err != nil {
+		return err
+	}
+	if option == "2" {
+		return execSecretKeys(nil, []string{secretName})
+	}
+
+	keys, err := c.GetSecretKeys(ctx, currentNamespace, secretName)
+	if err != nil {
+		return err
+	}
+	key, err := selectDataKey(secretName, keys)
+	if err != nil {
+		return err
+	}
+	if key == "" {
+		return nil
+	}
+	switch option {
+	case "3":
+		secretReveal = false
+		return execSecretGet(nil, []string{secretName, key})
+	case "4":
+		secretReveal = true
+		return execSecretGet(nil, []string{secretName, key})
+	case "5":
+		return execSecretEdit(nil, []string{secretName, key})
+	}
+
+	return nil
+}
+
+func execSecretList(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	c, _, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	secrets, err := c.GetSecrets(ctx, currentNamespace)
+	if err != nil {
+		return err
+	}
+	if len(secrets) == 0 {
+		fmt.Printf("No resources found in %s namespace.\n", currentNamespace)
+		return nil
+	}
+	w := newTableWriter()
+	fmt.Fprintln(w, "NAME\tTYPE\tKEYS\tAGE")
+	for _, secret := range secrets {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", secret.Name, secret.Type, secret.Keys, formatAge(secret.Created))
+	}
+
+	return w.Flush()
+}
+
+func execSecretKeys(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	secretName := args[0]
+	c, _, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	keys, err := c.GetSecretKeys(ctx, currentNamespace, secretName)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		fmt.Printf("Secret %s has no keys.\n", secretName)
+		return nil
+	}
+
+	return printDataKeys(keys)
+}
+
+func execSecretGet(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	secretName := args[0]
+	key := args[1]
+	c, currentContext, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	value, _, err := c.GetSecretValue(ctx, currentNamespace, secretName, key)
+	if err != nil {
+		return err
+	}
+	if !secretReveal {
+		fmt.Printf("******** (%d bytes, use --reveal to show the decoded value)\n", len(value))
+		return nil
+	}
+	if err := confirmProtectedContext(currentContext, "reveal secret "+secretName); err != nil {
+		return err
+	}
+	fmt.Println(string(value))
+
+	return nil
+}
+
+func execSecretEdit(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	secretName := args[0]
+	key := args[1]
+	c, currentContext, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	if err := confirmProtectedContext(currentContext, "reveal and edit secret "+secretName); err != nil {
+		return err
+	}
+	value, resourceVersion, err := c.GetSecretValue(ctx, currentNamespace, secretName, key)
+	if err != nil {
+		return err
+	}
+	edited, err := editInEditor(key, string(value))
+	if err != nil {
+		return err
+	}
+	apply, err := confirmChanges(secretName+"/"+key, string(value), edited)
+	if err != nil || !apply {
+		return err
+	}
+	err = c.SetSecretValue(ctx, currentNamespace, secretName, key, []byte(edited), resourceVersion)
+	if internal.IsConflict(err) {
+		return fmt.Errorf("secret %s was changed while it was edited, edit it again: %w", secretName, err)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Key %s of secret %s updated.\n", key, secretName)
+
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(secretCmd)
+	secretCmd.AddCommand(secretListCmd)
+	secretCmd.AddCommand(secretKeysCmd)
+	secretCmd.AddCommand(secretGetCmd)
+	secretCmd.AddCommand(secretEditCmd)
+	secretGetCmd.Flags().BoolVar(&secretReveal, "reveal", false, "Show the base64 decoded value instead of masking it.")
+}
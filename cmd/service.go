@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"charm.land/huh/v2"
+	"github.com/spf13/cobra"
+	"github.com/wim-vdw/wimkube/internal"
+)
+
+var serviceCmd = &cobra.Command{
+	Use:     "service",
+	Aliases: []string{"svc"},
+	Short:   "Manage services.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return loopMenu(showServiceMenu)
+	},
+}
+
+var serviceListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all services.",
+	RunE:  execServiceList,
+}
+
+var serviceDescribeCmd = &cobra.Command{
+	Use:   "describe [service-name]",
+	Short: "Describe a service and its endpoints.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  execServiceDescribe,
+}
+
+func showServiceMenu() error {
+	ctx := commandContext(nil)
+	var option string
+	c, _, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("Select an option (namespace: %s)", currentNamespace)
+	options := menuOptions(ctx, c, currentNamespace, []menuAction{
+		{"List all services", "1", nil},
+		{"Describe a service", "2", nil},
+		{"Get the logs of a container of a backing pod", "3", nil},
+		{"Execute an interactive shell in a container of a backing pod", "4", &accessExec},
+	})
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title(title).
+				Options(options...).
+				Value(&option),
+		),
+	)
+	err = runMenu(form)
+	if err != nil {
+		return err
+	}
+	if option == "1" {
+		return execServiceList(nil, nil)
+	}
+
+	serviceName, err := selectService(ctx, currentNamespace, c)
+	if err != nil {
+		return err
+	}
+	if serviceName == "" {
+		return nil
+	}
+	if option == "2" {
+		return execServiceDescribe(nil, []string{serviceName})
+	}
+
+	endpoints, err := c.GetServiceEndpoints(ctx, currentNamespace, serviceName)
+	if err != nil {
+		return err
+	}
+	pods := servicePods(endpoints)
+	if len(pods) == 0 {
+		fmt.Printf("Service %s has no backing pods.\n", serviceName)
+		return nil
+	}
+	podName, containerName, err := selectPodAndContainerFrom(ctx, currentNamespace, pods, c)
+	if err != nil {
+		return err
+	}
+	switch option {
+	case "3":
+		return execPodContainerLogs(nil, []string{podName, containerName})
+	case "4":
+		return execPodContainerExec(nil, []string{podName, containerName})
+	}
+
+	return nil
+}
+
+func selectService(ctx context.Context, currentNamespace string, c *internal.Client) (string, error) {
+	services, err := c.GetServices(ctx, currentNamespace)
+	if err != nil {
+		return "", err
+	}
+	if len(services) == 0 {
+		fmt.Printf("No resources found in %s namespace.\n", currentNamespace)
+		return "", nil
+	}
+	serviceNames := make([]string, 0, len(services))
+	for _, svc := range services {
+		serviceNames = append(serviceNames, svc.Name)
+	}
+
+	title := fmt.Sprintf("Select a service (namespace: %s)", currentNamespace)
+	serviceName, err := pickValue(title, serviceNames, "", "")
+	if err != nil {
+		return "", err
+	}
+
+	return serviceName, nil
+}
+
+// servicePods returns the unique names of the pods backing a service, ready pods first.
+func servicePods(endpoints []internal.Endpoint) []string {
+	var ready, notReady []string
+	seen := make(map[string]bool)
+	for _, ep := range endpoints {
+		if ep.PodName == "" || seen[ep.PodName] {
+			continue
+		}
+		seen[ep.PodName] = true
+		if ep.Ready {
+			ready = append(ready, ep.PodName)
+		} else {
+			notReady = append(notReady, ep.PodName)
+		}
+	}
+
+	return append(ready, notReady...)
+}
+
+func execServiceList(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	c, _, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	services, err := c.GetServices(ctx, currentNamespace)
+	if err != nil {
+		return err
+	}
+	if len(services) == 0 {
+		fmt.Printf("No resources found in %s namespace.\n", currentNamespace)
+		return nil
+	}
+	w := newTableWriter()
+	fmt.Fprintln(w, "NAME\tTYPE\tCLUSTER-IP\tEXTERNAL-IP\tPORT(S)\tAGE")
+	for _, svc := range services {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", svc.Name, svc.Type, valueOrNone(svc.ClusterIP), svc.ExternalIP, valueOrNone(svc.Ports), formatAge(svc.Created))
+	}
+
+	return w.Flush()
+}
+
+func execServiceDescribe(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	serviceName := args[0]
+	c, _, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	svc, err := c.GetService(ctx, currentNamespace, serviceName)
+	if err != nil {
+		return err
+	}
+	endpoints, err := c.GetServiceEndpoints(ctx, currentNamespace, serviceName)
+	if err != nil {
+		return err
+	}
+
+	w := newTableWriter()
+	fmt.Fprintf(w, "Name:\t%s\n", svc.Name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", svc.Namespace)
+	fmt.Fprintf(w, "Type:\t%s\n", svc.Type)
+	fmt.Fprintf(w, "Selector:\t%s\n", valueOrNone(svc.Selector))
+	fmt.Fprintf(w, "Cluster IP:\t%s\n", valueOrNone(svc.ClusterIP))
+	fmt.Fprintf(w, "External IP:\t%s\n", svc.ExternalIP)
+	fmt.Fprintf(w, "Ports:\t%s\n", valueOrNone(svc.Ports))
+	fmt.Fprintf(w, "Age:\t%s\n", formatAge(svc.Created))
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Println("Endpoints:")
+	if len(endpoints) == 0 {
+		if svc.Selector == "" {
+			fmt.Println("  <none> (service has no selector)")
+		} else {
+			fmt.Println("  <none> (no pods match the selector)")
+		}
+		return nil
+	}
+	ready := 0
+	w = newTableWriter()
+	fmt.Fprintln(w, "  ADDRESS\tPORT(S)\tPOD\tNODE\tREADY")
+	for _, ep := range endpoints {
+		if ep.Ready {
+			ready++
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%t\n", ep.Address, valueOrNone(ep.Ports), valueOrNone(ep.PodName), valueOrNone(ep.NodeName), ep.Ready)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("%d of %d endpoints ready.\n", ready, len(endpoints))
+
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(serviceCmd)
+	serviceCmd.AddCommand(serviceListCmd)
+	serviceCmd.AddCommand(serviceDescribeCmd)
+}
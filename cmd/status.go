@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wim-vdw/wimkube/internal"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+var (
+	statusOutput string
+	statusSince  time.Duration
+)
+
+var statusCmd = &cobra.Command{
+	Use:         "status",
+	Short:       "Display a health summary of the cluster of the current context.",
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{fanoutAnnotation: "true"},
+	RunE:        execStatus,
+}
+
+// contextStatus is the JSON representation of the status of the cluster of a context.
+type contextStatus struct {
+	Context string `json:"context"`
+	*internal.ClusterStatus
+}
+
+func execStatus(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	if err := checkOutput(statusOutput, outputJSON); err != nil {
+		return err
+	}
+	if isFanout() {
+		return execStatusFanout(ctx)
+	}
+	currentContext, err := resolveContext()
+	if err != nil {
+		return err
+	}
+	c, err := newClient(currentContext)
+	if err != nil {
+		return err
+	}
+	status := c.GetClusterStatus(ctx, statusSince)
+	if statusOutput == outputJSON {
+		return printJSON(contextStatus{currentContext, status})
+	}
+
+	return printStatus(currentContext, status)
+}
+
+func execStatusFanout(ctx context.Context) error {
+	contexts, err := selectedContexts()
+	if err != nil {
+		return err
+	}
+	results := fanOut(contexts, func(contextName string) (*internal.ClusterStatus, error) {
+		c, _, err := contextClient(contextName)
+		if err != nil {
+			return nil, err
+		}
+		return c.GetClusterStatus(ctx, statusSince), nil
+	})
+
+	if statusOutput == outputJSON {
+		statuses := make([]contextStatus, 0, len(results))
+		for _, result := range results {
+			if result.Err == nil {
+				statuses = append(statuses, contextStatus{result.Context, result.Value})
+			}
+		}
+		if err := printJSON(statuses); err != nil {
+			return err
+		}
+		return reportFanoutErrors(results)
+	}
+
+	w := newTableWriter()
+	fmt.Fprintln(w, "CONTEXT\tHEALTH\tVERSION\tNODES READY\tPODS NOT RUNNING\tUNAVAILABLE DEPLOYMENTS\tWARNINGS\tERRORS")
+	for _, result := range results {
+		if result.Err != nil {
+			continue
+		}
+		status := result.Value
+		health := "OK"
+		switch {
+		case !status.Reachable:
+			health = "Unreachable"
+		case !status.Healthy:
+			health = "Issues"
+		}
+		pods := 0
+		for _, group := range status.Pods {
+			pods += len(group.Pods)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d/%d\t%d\t%d\t%d\t%d\n", result.Context, health, valueOrNone(status.Version),
+			status.Nodes.Ready, status.Nodes.Total, pods, len(status.Deployments), len(status.Warnings), len(status.Errors))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	return reportFanoutErrors(results)
+}
+
+// printStatus prints a cluster status as a human-readable report.
+func printStatus(contextName string, status *internal.ClusterStatus) error {
+	w := newTableWriter()
+	fmt.Fprintf(w, "Context:\t%s\n", contextName)
+	if status.Reachable {
+		fmt.Fprintf(w, "API server:\t%s (reachable, %s)\n", status.Server, valueOrNone(status.Version))
+	} else {
+		fmt.Fprintf(w, "API server:\t%s (unreachable)\n", status.Server)
+	}
+	fmt.Fprintf(w, "Nodes:\t%d/%d ready\n", status.Nodes.Ready, status.Nodes.Total)
+	if status.Healthy {
+		fmt.Fprintf(w, "Health:\tOK\n")
+	} else {
+		fmt.Fprintf(w, "Health:\tissues found\n")
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(status.Pods) > 0 {
+		count := 0
+		for _, group := range status.Pods {
+			count += len(group.Pods)
+		}
+		fmt.Printf("\nPods not running (%d):\n", count)
+		w = newTableWriter()
+		fmt.Fprintln(w, "  NAMESPACE\tREASON\tCOUNT\tPODS")
+		for _, group := range status.Pods {
+			fmt.Fprintf(w, "  %s\t%s\t%d\t%s\n", group.Namespace, group.Reason, len(group.Pods), strings.Join(group.Pods, ", "))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if len(status.Deployments) > 0 {
+		fmt.Printf("\nDeployments with unavailable replicas (%d):\n", len(status.Deployments))
+		w = newTableWriter()
+		fmt.Fprintln(w, "  NAMESPACE\tNAME\tREADY\tUNAVAILABLE")
+		for _, d := range status.Deployments {
+			fmt.Fprintf(w, "  %s\t%s\t%d/%d\t%d\n", d.Namespace, d.Name, d.Ready, d.Desired, d.Unavailable)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if len(status.Warnings) > 0 {
+		fmt.Printf("\nWarning events (last %s, %d):\n", duration.HumanDuration(statusSince), len(status.Warnings))
+		w = newTableWriter()
+		fmt.Fprintln(w, "  LAST SEEN\tNAMESPACE\tOBJECT\tREASON\tMESSAGE")
+		for _, event := range status.Warnings {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", formatAge(event.LastSeen), event.Namespace, event.Object, event.Reason, event.Message)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if len(status.Errors) > 0 {
+		fmt.Println("\nErrors:")
+		for _, e := range status.Errors {
+			fmt.Printf("  %s\n", e)
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+	addOutputFlag(statusCmd, &statusOutput, outputJSON)
+	statusCmd.Flags().DurationVar(&statusSince, "since", time.Hour, "Show Warning events seen within this duration.")
+}
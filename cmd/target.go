@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/wim-vdw/wimkube/internal"
+)
+
+// namespaceOverride is the namespace given with --namespace.
+var namespaceOverride string
+
+// resolveTarget resolves the context and namespace of this invocation and creates a client for the context.
+// It returns the client, the context and the namespace and an error if they cannot be resolved.
+func resolveTarget() (*internal.Client, string, string, error) {
+	contextName, err := resolveContext()
+	if err != nil {
+		return nil, "", "", err
+	}
+	namespace, err := resolveNamespace(contextName)
+	if err != nil {
+		return nil, "", "", err
+	}
+	c, err := newClient(contextName)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	return c, contextName, namespace, nil
+}
+
+// resolveContext returns the context given with --context, or the current context of the kubeconfig.
+// It returns an error if the context does not exist.
+func resolveContext() (string, error) {
+	if len(contextPatterns) == 1 && !isFanout() {
+		contextName := contextPatterns[0]
+		// Looking up the namespace of the context verifies that the context exists.
+		if _, err := kubeConfig.GetContextNamespace(contextName); err != nil {
+			return "", err
+		}
+		return contextName, nil
+	}
+
+	return kubeConfig.GetCurrentContext()
+}
+
+// resolveNamespace returns the namespace given with --namespace, or the namespace configured for the context.
+// It returns an error if the context does not exist.
+func resolveNamespace(contextName string) (string, error) {
+	if namespaceOverride != "" {
+		return namespaceOverride, nil
+	}
+
+	return kubeConfig.GetContextNamespace(contextName)
+}
+
+// defaultCacheTTL is how long discovery data and namespaces are cached if cache-ttl is not configured.
+const defaultCacheTTL = 10 * time.Minute
+
+// clientFactory creates the client for a context.
+type clientFactory func(contextName string) (*internal.Client, error)
+
+// createClient creates a client for the context using the kubeconfig of this invocation.
+// Tests replace it with a factory returning fake clients.
+var createClient clientFactory = func(contextName string) (*internal.Client, error) {
+	return internal.NewClient(viper.GetString("kubeconfig"), contextName, time.Duration(viper.GetInt("request-timeout"))*time.Second, newCache())
+}
+
+// clientEntry holds the client of a context once it has been created.
+type clientEntry struct {
+	mu     sync.Mutex
+	client *internal.Client
+}
+
+var (
+	clientsMu sync.Mutex
+	clients   = map[string]*clientEntry{}
+)
+
+// newClient returns the client for the context. The client of a context is created once per process, so the
+// kubeconfig is parsed and credential plugins are run only once, also when a menu runs several commands. A client that
+// cannot be created is not remembered, so e.g. a menu retries after the user logged in again.
+// Clients of different contexts are created concurrently during a fan-out.
+func newClient(contextName string) (*internal.Client, error) {
+	clientsMu.Lock()
+	entry, exists := clients[contextName]
+	if !exists {
+		entry = &clientEntry{}
+		clients[contextName] = entry
+	}
+	clientsMu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if entry.client == nil {
+		c, err := createClient(contextName)
+		if err != nil {
+			return nil, err
+		}
+		entry.client = c
+	}
+
+	return entry.client, nil
+}
+
+// newCache returns the on-disk cache for discovery data and namespaces, whose entries expire after cache-ttl from
+// the configuration file. It returns nil if cache-ttl is 0 or the cache directory cannot be determined.
+func newCache() *internal.Cache {
+	ttl := defaultCacheTTL
+	if viper.IsSet("cache-ttl") {
+		ttl = viper.GetDuration("cache-ttl")
+	}
+	if ttl <= 0 {
+		return nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil
+	}
+
+	return internal.NewCache(filepath.Join(dir, "wimkube"), ttl)
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wim-vdw/wimkube/internal"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNewClientCreatesOneClientPerContext(t *testing.T) {
+	newTestEnv(t, nil)
+	var created atomic.Int32
+	createClient = func(contextName string) (*internal.Client, error) {
+		created.Add(1)
+		return internal.NewClientFromInterface(fake.NewClientset(), time.Second), nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]*internal.Client, 10)
+	for i := range results {
+		wg.Go(func() {
+			c, err := newClient([]string{"dev", "prod"}[i%2])
+			if err != nil {
+				t.Errorf("newClient() error = %v", err)
+			}
+			results[i] = c
+		})
+	}
+	wg.Wait()
+
+	if got := created.Load(); got != 2 {
+		t.Errorf("created %d clients, want 2", got)
+	}
+	for i, c := range results {
+		if c != results[i%2] {
+			t.Errorf("newClient() returned a different client for the same context")
+		}
+	}
+}
+
+func TestNewClientRetriesAfterAnError(t *testing.T) {
+	newTestEnv(t, nil)
+	var created atomic.Int32
+	createClient = func(contextName string) (*internal.Client, error) {
+		if created.Add(1) == 1 {
+			return nil, errors.New("credential plugin failed")
+		}
+		return internal.NewClientFromInterface(fake.NewClientset(), time.Second), nil
+	}
+
+	if _, err := newClient("dev"); err == nil {
+		t.Fatalf("newClient() error = nil, want the error of the first attempt")
+	}
+	c, err := newClient("dev")
+	if err != nil || c == nil {
+		t.Fatalf("newClient() = %v, %v, want a client on the second attempt", c, err)
+	}
+	if again, _ := newClient("dev"); again != c || created.Load() != 2 {
+		t.Errorf("newClient() created %d clients, want the second one to be reused", created.Load())
+	}
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/wim-vdw/wimkube/internal"
+)
+
+var (
+	topSortBy     string
+	topSelector   string
+	topContainers bool
+)
+
+var topCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Display resource usage of pods and nodes.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var topPodsCmd = &cobra.Command{
+	Use:   "pods",
+	Short: "Display resource usage of pods compared to their requests and limits.",
+	Args:  cobra.NoArgs,
+	RunE:  execTopPods,
+}
+
+var topNodesCmd = &cobra.Command{
+	Use:   "nodes",
+	Short: "Display resource usage of nodes compared to their allocatable resources.",
+	Args:  cobra.NoArgs,
+	RunE:  execTopNodes,
+}
+
+func execTopPods(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	if err := validateSortBy(); err != nil {
+		return err
+	}
+	c, _, currentNamespace, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	pods, err := c.GetPodUsage(ctx, currentNamespace, topSelector)
+	if err != nil {
+		return err
+	}
+	if len(pods) == 0 {
+		fmt.Printf("No resources found in %s namespace.\n", currentNamespace)
+		return nil
+	}
+	sort.SliceStable(pods, func(i, j int) bool {
+		return usageLess(pods[j].Usage, pods[i].Usage)
+	})
+
+	w := newTableWriter()
+	if topContainers {
+		fmt.Fprintln(w, "POD\tCONTAINER\tCPU\tCPU/REQ\tCPU/LIM\tMEMORY\tMEM/REQ\tMEM/LIM")
+		for _, pod := range pods {
+			containers := pod.Containers
+			sort.SliceStable(containers, func(i, j int) bool {
+				return usageLess(containers[j].Usage, containers[i].Usage)
+			})
+			for _, container := range containers {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", pod.Name, container.Name, formatUsage(container.Usage))
+			}
+		}
+	} else {
+		fmt.Fprintln(w, "NAME\tCPU\tCPU/REQ\tCPU/LIM\tMEMORY\tMEM/REQ\tMEM/LIM")
+		for _, pod := range pods {
+			fmt.Fprintf(w, "%s\t%s\n", pod.Name, formatUsage(pod.Usage))
+		}
+	}
+
+	return w.Flush()
+}
+
+func execTopNodes(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	if err := validateSortBy(); err != nil {
+		return err
+	}
+	currentContext, err := resolveContext()
+	if err != nil {
+		return err
+	}
+	c, err := newClient(currentContext)
+	if err != nil {
+		return err
+	}
+	nodes, err := c.GetNodeUsage(ctx, topSelector)
+	if err != nil {
+		return err
+	}
+	if len(nodes) == 0 {
+		fmt.Println("No resources found.")
+		return nil
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return usageLess(nodes[j].Usage, nodes[i].Usage)
+	})
+
+	w := newTableWriter()
+	fmt.Fprintln(w, "NAME\tCPU\tCPU%\tCPU/REQ%\tMEMORY\tMEMORY%\tMEM/REQ%")
+	for _, node := range nodes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", node.Name,
+			formatCPU(node.CPU), percentage(node.CPU, node.AllocatableCPU), percentage(node.CPURequest, node.AllocatableCPU),
+			formatMemory(node.Memory), percentage(node.Memory, node.AllocatableMemory), percentage(node.MemoryRequest, node.AllocatableMemory))
+	}
+
+	return w.Flush()
+}
+
+func validateSortBy() error {
+	switch topSortBy {
+	case "", "cpu", "memory":
+		return nil
+	}
+
+	return fmt.Errorf("invalid value for --sort-by: %s (must be cpu or memory)", topSortBy)
+}
+
+// usageLess orders usage by the column selected with --sort-by, or by CPU and then memory if no column is selected.
+func usageLess(a, b internal.Usage) bool {
+	if topSortBy == "memory" {
+		return a.Memory < b.Memory
+	}
+	if topSortBy == "" && a.CPU == b.CPU {
+		return a.Memory < b.Memory
+	}
+
+	return a.CPU < b.CPU
+}
+
+// formatUsage formats usage as tab-separated columns: CPU, CPU/REQ, CPU/LIM, MEMORY, MEM/REQ and MEM/LIM.
+func formatUsage(u internal.Usage) string {
+	return fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s",
+		formatCPU(u.CPU), percentage(u.CPU, u.CPURequest), percentage(u.CPU, u.CPULimit),
+		formatMemory(u.Memory), percentage(u.Memory, u.MemoryRequest), percentage(u.Memory, u.MemoryLimit))
+}
+
+// formatCPU formats millicores in the same way kubectl top does (e.g. 250m).
+func formatCPU(milliCores int64) string {
+	return fmt.Sprintf("%dm", milliCores)
+}
+
+// formatMemory formats bytes as mebibytes in the same way kubectl top does (e.g. 128Mi).
+func formatMemory(bytes int64) string {
+	return fmt.Sprintf("%dMi", bytes/(1024*1024))
+}
+
+// percentage returns value as a percentage of total, or "-" if total is not set.
+func percentage(value, total int64) string {
+	if total == 0 {
+		return "-"
+	}
+
+	return fmt.Sprintf("%d%%", value*100/total)
+}
+
+func init() {
+	rootCmd.AddCommand(topCmd)
+	topCmd.AddCommand(topPodsCmd)
+	topCmd.AddCommand(topNodesCmd)
+	topCmd.PersistentFlags().StringVar(&topSortBy, "sort-by", "", "Sort by cpu or memory (highest usage first).")
+	topCmd.PersistentFlags().StringVarP(&topSelector, "selector", "l", "", "Label selector to filter on, e.g. app=api.")
+	topPodsCmd.Flags().BoolVar(&topContainers, "containers", false, "Display the usage of each container.")
+}
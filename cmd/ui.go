@@ -0,0 +1,595 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/viewport"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/spf13/cobra"
+	"github.com/wim-vdw/wimkube/internal"
+)
+
+var uiRefreshInterval time.Duration
+
+// uiMinRefreshInterval is the shortest interval between automatic refreshes, so the dashboard does not flood the API
+// server with requests.
+const uiMinRefreshInterval = time.Second
+
+var uiCmd = &cobra.Command{
+	Use:   "ui",
+	Short: "Open a full-screen dashboard of contexts, namespaces and pods.",
+	Args:  cobra.NoArgs,
+	RunE:  execUI,
+}
+
+type uiPane int
+
+const (
+	paneContexts uiPane = iota
+	paneNamespaces
+	panePods
+)
+
+type uiMode int
+
+const (
+	modeBrowse uiMode = iota
+	modeViewer
+	modeContainerPicker
+	modeConfirmDelete
+)
+
+// uiModel is the bubbletea model of the dashboard. The selected context and namespace only apply to the
+// dashboard itself; the kubeconfig file is never modified.
+type uiModel struct {
+	ctx        context.Context
+	client     *internal.Client
+	contexts   []string
+	namespaces []string
+	pods       []internal.PodSummary
+	context    string
+	namespace  string
+	refreshed  time.Time
+
+	focus  uiPane
+	cursor [3]int
+	mode   uiMode
+	width  int
+	height int
+	status string
+
+	viewer      viewport.Model
+	viewerTitle string
+	picker      []string
+	pickerIndex int
+	pickerFor   string
+	pickerPod   string
+	deletePod   string
+}
+
+type uiTickMsg time.Time
+
+type uiPodsMsg struct {
+	context   string
+	namespace string
+	pods      []internal.PodSummary
+	err       error
+}
+
+type uiNamespacesMsg struct {
+	context    string
+	namespaces []string
+	err        error
+}
+
+type uiClientMsg struct {
+	context   string
+	namespace string
+	client    *internal.Client
+	err       error
+}
+
+type uiTextMsg struct {
+	title   string
+	content string
+	err     error
+}
+
+type uiStatusMsg struct {
+	status string
+	err    error
+}
+
+var (
+	uiHeaderStyle  = lipgloss.NewStyle().Bold(true)
+	uiPaneStyle    = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("240"))
+	uiFocusStyle   = uiPaneStyle.BorderForeground(lipgloss.Color("62"))
+	uiCursorStyle  = lipgloss.NewStyle().Reverse(true)
+	uiHelpStyle    = lipgloss.NewStyle().Faint(true)
+	uiWarningStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("203")).Bold(true)
+)
+
+func execUI(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	if uiRefreshInterval < uiMinRefreshInterval {
+		return fmt.Errorf("invalid value for --refresh: %s (must be at least %s)", uiRefreshInterval, uiMinRefreshInterval)
+	}
+	currentContext, err := resolveContext()
+	if err != nil {
+		return err
+	}
+	currentNamespace, err := resolveNamespace(currentContext)
+	if err != nil {
+		return err
+	}
+	c, err := newClient(currentContext)
+	if err != nil {
+		return err
+	}
+	m := uiModel{
+		ctx:       ctx,
+		client:    c,
+		contexts:  kubeConfig.GetContextNames(),
+		context:   currentContext,
+		namespace: currentNamespace,
+		focus:     panePods,
+	}
+	m.cursor[paneContexts] = max(slices.Index(m.contexts, currentContext), 0)
+	_, err = tea.NewProgram(m, tea.WithContext(ctx)).Run()
+
+	return err
+}
+
+func (m uiModel) Init() tea.Cmd {
+	return tea.Batch(m.loadNamespaces(), m.loadPods(), m.tick())
+}
+
+func (m uiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.viewer.SetWidth(m.width)
+		m.viewer.SetHeight(m.bodyHeight())
+		return m, nil
+	case uiTickMsg:
+		return m, tea.Batch(m.loadPods(), m.tick())
+	case uiPodsMsg:
+		if msg.context != m.context || msg.namespace != m.namespace {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.status = msg.err.Error()
+			return m, nil
+		}
+		m.pods = msg.pods
+		m.refreshed = time.Now()
+		m.cursor[panePods] = min(m.cursor[panePods], max(len(m.pods)-1, 0))
+		return m, nil
+	case uiNamespacesMsg:
+		if msg.context != m.context {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.status = msg.err.Error()
+			return m, nil
+		}
+		m.namespaces = msg.namespaces
+		m.cursor[paneNamespaces] = max(slices.Index(m.namespaces, m.namespace), 0)
+		return m, nil
+	case uiClientMsg:
+		if msg.err != nil {
+			m.status = msg.err.Error()
+			return m, nil
+		}
+		m.client = msg.client
+		m.context = msg.context
+		m.namespace = msg.namespace
+		m.namespaces = nil
+		m.pods = nil
+		m.status = fmt.Sprintf("Switched to context %s.", m.context)
+		return m, tea.Batch(m.loadNamespaces(), m.loadPods())
+	case uiTextMsg:
+		if msg.err != nil {
+			m.status = msg.err.Error()
+			return m, nil
+		}
+		m.mode = modeViewer
+		m.viewerTitle = msg.title
+		m.viewer = viewport.New(viewport.WithWidth(m.width), viewport.WithHeight(m.bodyHeight()))
+		m.viewer.SetContent(msg.content)
+		m.viewer.GotoBottom()
+		return m, nil
+	case uiStatusMsg:
+		m.status = msg.status
+		if msg.err != nil {
+			m.status = msg.err.Error()
+		}
+		return m, m.loadPods()
+	case tea.KeyPressMsg:
+		return m.handleKey(msg)
+	}
+
+	if m.mode == modeViewer {
+		var cmd tea.Cmd
+		m.viewer, cmd = m.viewer.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m uiModel) handleKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	if key == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	switch m.mode {
+	case modeViewer:
+		if key == "esc" || key == "q" {
+			m.mode = modeBrowse
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.viewer, cmd = m.viewer.Update(msg)
+		return m, cmd
+	case modeContainerPicker:
+		switch key {
+		case "esc", "q":
+			m.mode = modeBrowse
+		case "up", "k":
+			m.pickerIndex = max(m.pickerIndex-1, 0)
+		case "down", "j":
+			m.pickerIndex = min(m.pickerIndex+1, len(m.picker)-1)
+		case "enter":
+			m.mode = modeBrowse
+			return m, m.podAction(m.pickerFor, m.pickerPod, m.picker[m.pickerIndex])
+		}
+		return m, nil
+	case modeConfirmDelete:
+		m.mode = modeBrowse
+		if key != "y" {
+			m.status = "Delete cancelled."
+			return m, nil
+		}
+		m.status = fmt.Sprintf("Deleting pod %s...", m.deletePod)
+		return m, m.deletePodCmd(m.deletePod)
+	}
+
+	switch key {
+	case "q":
+		return m, tea.Quit
+	case "tab":
+		m.focus = (m.focus + 1) % 3
+	case "shift+tab":
+		m.focus = (m.focus + 2) % 3
+	case "up", "k":
+		m.cursor[m.focus] = max(m.cursor[m.focus]-1, 0)
+	case "down", "j":
+		m.cursor[m.focus] = min(m.cursor[m.focus]+1, max(m.paneLen(m.focus)-1, 0))
+	case "r":
+		m.status = "Refreshing..."
+		return m, tea.Batch(m.loadNamespaces(), m.loadPods())
+	case "enter":
+		switch m.focus {
+		case paneContexts:
+			if len(m.contexts) > 0 {
+				return m, m.switchContext(m.contexts[m.cursor[paneContexts]])
+			}
+		case paneNamespaces:
+			if len(m.namespaces) > 0 {
+				m.namespace = m.namespaces[m.cursor[paneNamespaces]]
+				m.pods = nil
+				m.cursor[panePods] = 0
+				m.status = fmt.Sprintf("Switched to namespace %s.", m.namespace)
+				return m, m.loadPods()
+			}
+		case panePods:
+			return m.startPodAction("d")
+		}
+	case "l", "s", "d":
+		return m.startPodAction(key)
+	case "ctrl+d":
+		pod, ok := m.selectedPod()
+		if !ok {
+			return m, nil
+		}
+		m.mode = modeConfirmDelete
+		m.deletePod = pod.Name
+		m.status = fmt.Sprintf("Delete pod %s in namespace %s? (y/N)", pod.Name, m.namespace)
+		if isProtectedContext(m.context) {
+			m.status = fmt.Sprintf("Context %s is protected! ", m.context) + m.status
+		}
+	}
+
+	return m, nil
+}
+
+// startPodAction starts logs (l), exec (s) or describe (d) for the selected pod.
+// For logs and exec the user picks a container first if the pod has more than one.
+func (m uiModel) startPodAction(action string) (tea.Model, tea.Cmd) {
+	pod, ok := m.selectedPod()
+	if !ok {
+		return m, nil
+	}
+	if action == "d" || len(pod.Containers) == 1 {
+		return m, m.podAction(action, pod.Name, pod.Containers[0])
+	}
+	m.mode = modeContainerPicker
+	m.picker = pod.Containers
+	m.pickerIndex = 0
+	m.pickerFor = action
+	m.pickerPod = pod.Name
+
+	return m, nil
+}
+
+func (m uiModel) podAction(action, podName, containerName string) tea.Cmd {
+	ctx, c, namespace := m.ctx, m.client, m.namespace
+	switch action {
+	case "l":
+		return func() tea.Msg {
+			logs, err := c.GetPodLogs(ctx, namespace, podName, containerName)
+			return uiTextMsg{title: fmt.Sprintf("Logs of %s/%s", podName, containerName), content: logs, err: err}
+		}
+	case "s":
+		return tea.Exec(uiExecCommand{run: func() error {
+			return c.ExecInContainer(ctx, namespace, podName, containerName)
+		}}, func(err error) tea.Msg {
+			return uiStatusMsg{status: fmt.Sprintf("Shell in %s/%s closed.", podName, containerName), err: err}
+		})
+	case "d":
+		return func() tea.Msg {
+			details, err := c.GetPodDetails(ctx, namespace, podName)
+			if err != nil {
+				return uiTextMsg{err: err}
+			}
+			return uiTextMsg{title: "Pod " + podName, content: formatPodDetails(details)}
+		}
+	}
+
+	return nil
+}
+
+func (m uiModel) View() tea.View {
+	if m.width == 0 {
+		return tea.NewView("Loading...")
+	}
+
+	header := uiHeaderStyle.Render(fmt.Sprintf("wimkube  context: %s  namespace: %s", m.context, m.namespace))
+	if !m.refreshed.IsZero() {
+		header += uiHelpStyle.Render(fmt.Sprintf("  (refreshed %s ago)", formatAge(m.refreshed)))
+	}
+
+	var body string
+	switch m.mode {
+	case modeViewer:
+		body = uiHeaderStyle.Render(m.viewerTitle) + "\n" + m.viewer.View()
+	case modeContainerPicker:
+		body = m.renderPane("Select a container", m.picker, m.pickerIndex, true, m.width, m.bodyHeight())
+	default:
+		leftWidth := min(max(m.width/4, 24), 40)
+		contextsHeight := m.bodyHeight() / 2
+		left := lipgloss.JoinVertical(lipgloss.Left,
+			m.renderPane("Contexts", markCurrent(m.contexts, m.context), m.cursor[paneContexts], m.focus == paneContexts, leftWidth, contextsHeight),
+			m.renderPane("Namespaces", markCurrent(m.namespaces, m.namespace), m.cursor[paneNamespaces], m.focus == paneNamespaces, leftWidth, m.bodyHeight()-contextsHeight),
+		)
+		right := m.renderPane("Pods", m.podRows(m.width-leftWidth-2), m.cursor[panePods], m.focus == panePods, m.width-leftWidth, m.bodyHeight())
+		body = lipgloss.JoinHorizontal(lipgloss.Top, left, right)
+	}
+
+	var footer string
+	switch m.mode {
+	case modeViewer:
+		footer = "↑/↓ scroll • esc back"
+	case modeContainerPicker:
+		footer = "↑/↓ move • enter select • esc back"
+	default:
+		footer = "tab pane • ↑/↓ move • enter select • l logs • s shell • d describe • ctrl+d delete • r refresh • q quit"
+	}
+	footer = uiHelpStyle.Render(footer)
+	if m.status != "" {
+		style := uiHelpStyle
+		if m.mode == modeConfirmDelete {
+			style = uiWarningStyle
+		}
+		footer = style.Render(m.status) + "\n" + footer
+	} else {
+		footer = "\n" + footer
+	}
+
+	v := tea.NewView(lipgloss.JoinVertical(lipgloss.Left, header, body, footer))
+	v.AltScreen = true
+	v.WindowTitle = "wimkube"
+
+	return v
+}
+
+// renderPane renders a bordered list with the given title whose cursor line stays visible.
+func (m uiModel) renderPane(title string, items []string, cursor int, focused bool, width, height int) string {
+	style := uiPaneStyle
+	if focused {
+		style = uiFocusStyle
+	}
+	innerWidth, innerHeight := max(width-2, 1), max(height-3, 1)
+	offset := max(cursor-innerHeight+1, 0)
+	lines := []string{uiHeaderStyle.Render(truncate(title, innerWidth))}
+	for i := offset; i < len(items) && i < offset+innerHeight; i++ {
+		line := truncate(items[i], innerWidth)
+		if i == cursor && focused {
+			line = uiCursorStyle.Render(line + strings.Repeat(" ", innerWidth-lipgloss.Width(line)))
+		}
+		lines = append(lines, line)
+	}
+
+	return style.Width(width).Height(height).Render(strings.Join(lines, "\n"))
+}
+
+// podRows formats the pods as aligned rows that fit within width.
+func (m uiModel) podRows(width int) []string {
+	if len(m.pods) == 0 {
+		return []string{"No pods found."}
+	}
+	nameWidth := 4
+	for _, pod := range m.pods {
+		nameWidth = max(nameWidth, len(pod.Name))
+	}
+	nameWidth = min(nameWidth, max(width-45, 10))
+	rows := make([]string, 0, len(m.pods))
+	for _, pod := range m.pods {
+		rows = append(rows, fmt.Sprintf("%-*s  %-6s %-18s %-8d %s", nameWidth, truncate(pod.Name, nameWidth), pod.Ready, truncate(pod.Status, 18), pod.Restarts, formatAge(pod.Created)))
+	}
+
+	return rows
+}
+
+func (m uiModel) bodyHeight() int {
+	// The header takes one line and the footer two.
+	return max(m.height-3, 5)
+}
+
+func (m uiModel) paneLen(pane uiPane) int {
+	switch pane {
+	case paneContexts:
+		return len(m.contexts)
+	case paneNamespaces:
+		return len(m.namespaces)
+	}
+
+	return len(m.pods)
+}
+
+func (m uiModel) selectedPod() (internal.PodSummary, bool) {
+	if len(m.pods) == 0 {
+		return internal.PodSummary{}, false
+	}
+
+	return m.pods[m.cursor[panePods]], true
+}
+
+func (m uiModel) tick() tea.Cmd {
+	return tea.Tick(uiRefreshInterval, func(t time.Time) tea.Msg {
+		return uiTickMsg(t)
+	})
+}
+
+func (m uiModel) loadPods() tea.Cmd {
+	ctx, c, context, namespace := m.ctx, m.client, m.context, m.namespace
+	return func() tea.Msg {
+		pods, err := c.GetPodSummaries(ctx, namespace)
+		return uiPodsMsg{context: context, namespace: namespace, pods: pods, err: err}
+	}
+}
+
+func (m uiModel) loadNamespaces() tea.Cmd {
+	ctx, c, context := m.ctx, m.client, m.context
+	return func() tea.Msg {
+		namespaces, err := listNamespaces(ctx, c, context)
+		return uiNamespacesMsg{context: context, namespaces: namespaces, err: err}
+	}
+}
+
+func (m uiModel) switchContext(contextName string) tea.Cmd {
+	return func() tea.Msg {
+		namespace, err := kubeConfig.GetContextNamespace(contextName)
+		if err != nil {
+			return uiClientMsg{err: err}
+		}
+		c, err := newClient(contextName)
+		return uiClientMsg{context: contextName, namespace: namespace, client: c, err: err}
+	}
+}
+
+func (m uiModel) deletePodCmd(podName string) tea.Cmd {
+	ctx, c, namespace := m.ctx, m.client, m.namespace
+	return func() tea.Msg {
+		err := c.DeletePod(ctx, namespace, podName)
+		return uiStatusMsg{status: fmt.Sprintf("Pod %s deleted.", podName), err: err}
+	}
+}
+
+// uiExecCommand runs an interactive session while bubbletea has released the terminal.
+type uiExecCommand struct {
+	run func() error
+}
+
+func (e uiExecCommand) Run() error          { return e.run() }
+func (e uiExecCommand) SetStdin(io.Reader)  {}
+func (e uiExecCommand) SetStdout(io.Writer) {}
+func (e uiExecCommand) SetStderr(io.Writer) {}
+
+// formatPodDetails formats the details of a pod for the describe view.
+func formatPodDetails(pod *internal.PodDetails) string {
+	var sb strings.Builder
+	w := newTableWriterTo(&sb)
+	fmt.Fprintf(w, "Name:\t%s\n", pod.Name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", pod.Namespace)
+	fmt.Fprintf(w, "Node:\t%s\n", valueOrNone(pod.Node))
+	fmt.Fprintf(w, "Status:\t%s\n", pod.Status)
+	fmt.Fprintf(w, "IP:\t%s\n", valueOrNone(pod.IP))
+	fmt.Fprintf(w, "QoS class:\t%s\n", valueOrNone(pod.QoSClass))
+	fmt.Fprintf(w, "Started:\t%s\n", formatTimeAgo(pod.StartTime))
+	fmt.Fprintf(w, "Labels:\t%s\n", valueOrNone(strings.Join(pod.Labels, ", ")))
+	fmt.Fprintf(w, "Conditions:\t%s\n", valueOrNone(strings.Join(pod.Conditions, ", ")))
+	_ = w.Flush()
+
+	sb.WriteString("\nContainers:\n")
+	w = newTableWriterTo(&sb)
+	fmt.Fprintln(w, "  NAME\tIMAGE\tREADY\tRESTARTS\tSTATE")
+	for _, container := range pod.Containers {
+		fmt.Fprintf(w, "  %s\t%s\t%t\t%d\t%s\n", container.Name, container.Image, container.Ready, container.Restarts, container.State)
+	}
+	_ = w.Flush()
+
+	sb.WriteString("\nEvents:\n")
+	if len(pod.Events) == 0 {
+		sb.WriteString("  <none>\n")
+		return sb.String()
+	}
+	w = newTableWriterTo(&sb)
+	fmt.Fprintln(w, "  TYPE\tREASON\tAGE\tMESSAGE")
+	for _, event := range pod.Events {
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", event.Type, event.Reason, formatAge(event.LastSeen), event.Message)
+	}
+	_ = w.Flush()
+
+	return sb.String()
+}
+
+// markCurrent prefixes the current item with an asterisk.
+func markCurrent(items []string, current string) []string {
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if item == current {
+			out = append(out, "* "+item)
+		} else {
+			out = append(out, "  "+item)
+		}
+	}
+
+	return out
+}
+
+// truncate shortens s to at most width characters, adding an ellipsis if it was shortened.
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	if width <= 1 {
+		return string(runes[:width])
+	}
+
+	return string(runes[:width-1]) + "…"
+}
+
+func init() {
+	rootCmd.AddCommand(uiCmd)
+	uiCmd.Flags().DurationVar(&uiRefreshInterval, "refresh", 5*time.Second, "Interval between automatic refreshes of the pod list.")
+}
@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUIRefreshInterval(t *testing.T) {
+	env := newTestEnv(t, nil)
+	for _, refresh := range []string{"0", "-5s", "100ms"} {
+		if _, err := env.run(t, "ui", "--refresh", refresh); err == nil || !strings.Contains(err.Error(), "invalid value for --refresh") {
+			t.Errorf("ui --refresh %s error = %v, want an invalid --refresh", refresh, err)
+		}
+	}
+}
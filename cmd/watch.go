@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wim-vdw/wimkube/internal"
+	"golang.org/x/term"
+)
+
+// watchRefreshInterval is the interval at which the live table is redrawn to keep the AGE column up to date.
+const watchRefreshInterval = 5 * time.Second
+
+// watchConditions are the conditions supported by pod list --until.
+var watchConditions = map[string]func(pods map[string]internal.PodSummary) bool{
+	// ready is met when there is at least one pod and all pods are ready or have completed.
+	"ready": func(pods map[string]internal.PodSummary) bool {
+		if len(pods) == 0 {
+			return false
+		}
+		for _, pod := range pods {
+			if !podReady(pod) {
+				return false
+			}
+		}
+		return true
+	},
+	// empty is met when all pods have been deleted.
+	"empty": func(pods map[string]internal.PodSummary) bool {
+		return len(pods) == 0
+	},
+}
+
+// podWatcher keeps track of the pods reported by a watch and writes them to stdout.
+type podWatcher struct {
+	mu        sync.Mutex
+	namespace string
+	pods      map[string]internal.PodSummary
+	live      bool
+	lines     int
+	until     func(pods map[string]internal.PodSummary) bool
+}
+
+// watchPods watches the pods in the namespace until ctx is cancelled, e.g. by Ctrl+C, or the condition is met.
+// On a terminal the pods are shown in a table that is redrawn in place, otherwise every change is printed as a line.
+func watchPods(ctx context.Context, c *internal.Client, namespace, condition string) error {
+	w := &podWatcher{
+		namespace: namespace,
+		pods:      make(map[string]internal.PodSummary),
+		live:      term.IsTerminal(int(os.Stdout.Fd())),
+	}
+	if condition != "" {
+		until, exists := watchConditions[condition]
+		if !exists {
+			return fmt.Errorf("invalid value for --until: %s (must be ready or empty)", condition)
+		}
+		w.until = until
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if w.live {
+		go func() {
+			ticker := time.NewTicker(watchRefreshInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					w.mu.Lock()
+					w.redraw()
+					w.mu.Unlock()
+				}
+			}
+		}()
+	} else {
+		fmt.Fprintln(os.Stdout, "EVENT\tNAME\tREADY\tSTATUS\tRESTARTS\tAGE")
+	}
+
+	met := false
+	err := c.WatchPods(ctx, namespace, func(events []internal.PodEvent) bool {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		for _, event := range events {
+			if event.Type == "DELETED" {
+				delete(w.pods, event.Pod.Name)
+			} else {
+				w.pods[event.Pod.Name] = event.Pod
+			}
+			if !w.live {
+				pod := event.Pod
+				fmt.Fprintf(os.Stdout, "%s\t%s\t%s\t%s\t%d\t%s\n", event.Type, pod.Name, pod.Ready, pod.Status, pod.Restarts, formatAge(pod.Created))
+			}
+		}
+		if w.live {
+			w.redraw()
+		}
+		met = w.until != nil && w.until(w.pods)
+		return !met
+	})
+	if err != nil {
+		return err
+	}
+	if met && w.live {
+		fmt.Printf("Condition %s met.\n", condition)
+	}
+
+	return nil
+}
+
+// redraw replaces the table that was written previously with the current pods.
+func (w *podWatcher) redraw() {
+	var buf bytes.Buffer
+	if w.lines > 0 {
+		// Move the cursor to the start of the previous table and clear everything below it.
+		fmt.Fprintf(&buf, "\033[%dA\033[J", w.lines)
+	}
+	var table bytes.Buffer
+	if len(w.pods) == 0 {
+		fmt.Fprintf(&table, "No resources found in %s namespace.\n", w.namespace)
+	} else {
+		names := make([]string, 0, len(w.pods))
+		for name := range w.pods {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		tw := newTableWriterTo(&table)
+		fmt.Fprintln(tw, "NAME\tREADY\tSTATUS\tRESTARTS\tAGE")
+		for _, name := range names {
+			pod := w.pods[name]
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", pod.Name, pod.Ready, pod.Status, pod.Restarts, formatAge(pod.Created))
+		}
+		_ = tw.Flush()
+	}
+	w.lines = strings.Count(table.String(), "\n")
+	buf.Write(table.Bytes())
+	_, _ = os.Stdout.Write(buf.Bytes())
+}
+
+// podReady reports whether all containers of a pod are ready, or whether the pod has completed.
+func podReady(pod internal.PodSummary) bool {
+	if pod.Status == "Completed" {
+		return true
+	}
+	ready, total, _ := strings.Cut(pod.Ready, "/")
+
+	return pod.Status == "Running" && ready == total
+}
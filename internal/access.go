@@ -0,0 +1,156 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Access is an action on a resource whose permission can be checked with CanI.
+// An empty namespace checks the action in all namespaces, or for cluster-scoped resources.
+type Access struct {
+	Verb        string
+	Group       string
+	Resource    string
+	Subresource string
+	Name        string
+	Namespace   string
+}
+
+// String returns the access the way kubectl auth can-i takes it, e.g. "create pods/exec".
+func (a Access) String() string {
+	resource := a.Resource
+	if a.Group != "" {
+		resource += "." + a.Group
+	}
+	if a.Subresource != "" {
+		resource += "/" + a.Subresource
+	}
+	if a.Name != "" {
+		resource += " " + a.Name
+	}
+
+	return a.Verb + " " + resource
+}
+
+// Rule is a rule of the RBAC roles that apply to the user in a namespace.
+type Rule struct {
+	Verbs           []string `json:"verbs"`
+	APIGroups       []string `json:"apiGroups,omitempty"`
+	Resources       []string `json:"resources,omitempty"`
+	ResourceNames   []string `json:"resourceNames,omitempty"`
+	NonResourceURLs []string `json:"nonResourceURLs,omitempty"`
+}
+
+// Allows reports whether the rule allows the verb on the resource, e.g. "pods" or "pods/exec", in the API group.
+// It reports partial as true if the rule allows it only for specific resource names.
+func (r Rule) Allows(verb, group, resource string) (allowed, partial bool) {
+	if !matches(r.Verbs, verb) || !matches(r.APIGroups, group) {
+		return false, false
+	}
+	if !matches(r.Resources, resource) {
+		// A rule for "pods/*" covers all subresources of pods, "*/scale" the scale subresource of all resources.
+		base, sub, isSubresource := strings.Cut(resource, "/")
+		if !isSubresource || !(slices.Contains(r.Resources, base+"/*") || slices.Contains(r.Resources, "*/"+sub)) {
+			return false, false
+		}
+	}
+
+	return true, len(r.ResourceNames) > 0
+}
+
+// matches reports whether the values of a rule contain value or the wildcard.
+func matches(values []string, value string) bool {
+	return slices.Contains(values, "*") || slices.Contains(values, value)
+}
+
+// CanI asks the API server whether the user of the client may perform the access, using a SelfSubjectAccessReview.
+// It returns the decision, the reason the authorizer gave for it, and an error if the review cannot be created.
+func (c *Client) CanI(ctx context.Context, access Access) (bool, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   access.Namespace,
+				Verb:        access.Verb,
+				Group:       access.Group,
+				Resource:    access.Resource,
+				Subresource: access.Subresource,
+				Name:        access.Name,
+			},
+		},
+	}
+	review, err := c.client.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return false, "", fmt.Errorf("unable to check whether you can %s: %w", access, err)
+	}
+
+	return review.Status.Allowed && !review.Status.Denied, review.Status.Reason, nil
+}
+
+// GetRules retrieves the rules that apply to the user of the client in the namespace, using a SelfSubjectRulesReview.
+// The rules are incomplete if the cluster uses an authorizer that cannot list rules, e.g. a webhook; the reason is
+// returned as a non-empty string. It returns an error if the review cannot be created.
+func (c *Client) GetRules(ctx context.Context, namespace string) ([]Rule, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	review := &authorizationv1.SelfSubjectRulesReview{
+		Spec: authorizationv1.SelfSubjectRulesReviewSpec{Namespace: namespace},
+	}
+	review, err := c.client.AuthorizationV1().SelfSubjectRulesReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to get the rules in namespace %s: %w", namespace, err)
+	}
+	rules := make([]Rule, 0, len(review.Status.ResourceRules)+len(review.Status.NonResourceRules))
+	for _, rule := range review.Status.ResourceRules {
+		rules = append(rules, Rule{
+			Verbs:         rule.Verbs,
+			APIGroups:     rule.APIGroups,
+			Resources:     rule.Resources,
+			ResourceNames: rule.ResourceNames,
+		})
+	}
+	for _, rule := range review.Status.NonResourceRules {
+		rules = append(rules, Rule{Verbs: rule.Verbs, NonResourceURLs: rule.NonResourceURLs})
+	}
+	var incomplete string
+	if review.Status.Incomplete {
+		incomplete = review.Status.EvaluationError
+		if incomplete == "" {
+			incomplete = "the authorizer of the cluster cannot list all rules"
+		}
+	}
+
+	return rules, incomplete, nil
+}
+
+// ResolveResource resolves a resource the way kubectl takes it, e.g. "po", "deploy.apps" or "pods/exec", to the
+// group, resource and subresource known by the API server, using the cached discovery data.
+// It returns an error if the server does not have the resource.
+func (c *Client) ResolveResource(ctx context.Context, resource string) (group, name, subresource string, err error) {
+	resource, subresource, _ = strings.Cut(resource, "/")
+	if resource == "*" {
+		return "*", "*", subresource, nil
+	}
+	gvr, gr := schema.ParseResourceArg(resource)
+	partial := gr.WithVersion("")
+	if gvr != nil {
+		partial = *gvr
+	}
+	resolved, err := withTimeout(ctx, c, func() (schema.GroupVersionResource, error) {
+		return c.restMapper().ResourceFor(partial)
+	})
+	if err != nil {
+		return "", "", "", fmt.Errorf("the server doesn't have a resource type %s: %w", resource, err)
+	}
+
+	return resolved.Group, resolved.Resource, subresource, nil
+}
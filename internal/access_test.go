@@ -0,0 +1,120 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestRuleAllows(t *testing.T) {
+	tests := []struct {
+		name        string
+		rule        Rule
+		verb        string
+		group       string
+		resource    string
+		wantAllowed bool
+		wantPartial bool
+	}{
+		{
+			name:        "exact match",
+			rule:        Rule{Verbs: []string{"get", "list"}, APIGroups: []string{""}, Resources: []string{"pods"}},
+			verb:        "get",
+			resource:    "pods",
+			wantAllowed: true,
+		},
+		{
+			name:     "other verb",
+			rule:     Rule{Verbs: []string{"get", "list"}, APIGroups: []string{""}, Resources: []string{"pods"}},
+			verb:     "delete",
+			resource: "pods",
+		},
+		{
+			name:     "other group",
+			rule:     Rule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"deployments"}},
+			verb:     "get",
+			group:    "apps",
+			resource: "deployments",
+		},
+		{
+			name:        "wildcards",
+			rule:        Rule{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}},
+			verb:        "delete",
+			group:       "apps",
+			resource:    "deployments",
+			wantAllowed: true,
+		},
+		{
+			name:        "all subresources",
+			rule:        Rule{Verbs: []string{"create"}, APIGroups: []string{""}, Resources: []string{"pods/*"}},
+			verb:        "create",
+			resource:    "pods/exec",
+			wantAllowed: true,
+		},
+		{
+			name:        "subresource of all resources",
+			rule:        Rule{Verbs: []string{"patch"}, APIGroups: []string{"apps"}, Resources: []string{"*/scale"}},
+			verb:        "patch",
+			group:       "apps",
+			resource:    "deployments/scale",
+			wantAllowed: true,
+		},
+		{
+			name:     "resource does not cover its subresources",
+			rule:     Rule{Verbs: []string{"create"}, APIGroups: []string{""}, Resources: []string{"pods"}},
+			verb:     "create",
+			resource: "pods/exec",
+		},
+		{
+			name:        "resource names",
+			rule:        Rule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"app"}},
+			verb:        "get",
+			resource:    "secrets",
+			wantAllowed: true,
+			wantPartial: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, partial := tt.rule.Allows(tt.verb, tt.group, tt.resource)
+			if allowed != tt.wantAllowed || partial != tt.wantPartial {
+				t.Errorf("Allows() = %v, %v, want %v, %v", allowed, partial, tt.wantAllowed, tt.wantPartial)
+			}
+		})
+	}
+}
+
+func TestCanI(t *testing.T) {
+	clientset := fake.NewClientset()
+	clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		attributes := review.Spec.ResourceAttributes
+		review.Status.Allowed = attributes.Namespace == "payments" && attributes.Verb == "create" && attributes.Subresource == "exec"
+		return true, review, nil
+	})
+	c := NewClientFromInterface(clientset, time.Second)
+
+	tests := []struct {
+		access Access
+		want   bool
+	}{
+		{access: Access{Verb: "create", Resource: "pods", Subresource: "exec", Namespace: "payments"}, want: true},
+		{access: Access{Verb: "create", Resource: "pods", Subresource: "exec", Namespace: "default"}, want: false},
+		{access: Access{Verb: "delete", Resource: "pods", Namespace: "payments"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.access.String()+" in "+tt.access.Namespace, func(t *testing.T) {
+			got, _, err := c.CanI(t.Context(), tt.access)
+			if err != nil {
+				t.Fatalf("CanI() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CanI() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
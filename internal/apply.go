@@ -0,0 +1,244 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// FieldManager is the field manager of the changes wimkube applies with server-side apply.
+const FieldManager = "wimkube"
+
+// resourcePollInterval is the time between checks whether the server serves a new kind.
+const resourcePollInterval = time.Second
+
+// manifestExtensions are the extensions of the files read from a directory of manifests.
+var manifestExtensions = []string{".yaml", ".yml", ".json"}
+
+// ReadManifests reads the objects in a manifest file, in the manifest files of a directory (not recursively), or on
+// stdin if path is "-". A file can hold several YAML documents and List objects.
+// It returns an error if a file cannot be read or a document is not a valid object.
+func ReadManifests(path string) ([]*unstructured.Unstructured, error) {
+	if path == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read stdin: %w", err)
+		}
+		return ParseManifests("stdin", data)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", path, err)
+	}
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read directory %s: %w", path, err)
+		}
+		files = files[:0]
+		for _, entry := range entries {
+			if !entry.IsDir() && slices.Contains(manifestExtensions, filepath.Ext(entry.Name())) {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+
+	var objects []*unstructured.Unstructured
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s: %w", file, err)
+		}
+		parsed, err := ParseManifests(file, data)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, parsed...)
+	}
+
+	return objects, nil
+}
+
+// ParseManifests parses the YAML or JSON documents of a manifest into objects, expanding List objects into their
+// items. The name of the manifest is used in errors.
+// It returns an error if a document cannot be parsed or lacks its apiVersion, kind or name.
+func ParseManifests(name string, data []byte) ([]*unstructured.Unstructured, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	var objects []*unstructured.Unstructured
+	for document := 1; ; document++ {
+		object := &unstructured.Unstructured{}
+		err := decoder.Decode(&object.Object)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse document %d of %s: %w", document, name, err)
+		}
+		if len(object.Object) == 0 {
+			continue
+		}
+		items := []*unstructured.Unstructured{object}
+		if object.IsList() {
+			list, err := object.ToList()
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse document %d of %s: %w", document, name, err)
+			}
+			items = items[:0]
+			for i := range list.Items {
+				items = append(items, &list.Items[i])
+			}
+		}
+		for _, item := range items {
+			switch {
+			case item.GetAPIVersion() == "":
+				return nil, fmt.Errorf("document %d of %s has no apiVersion", document, name)
+			case item.GetKind() == "":
+				return nil, fmt.Errorf("document %d of %s has no kind", document, name)
+			case item.GetName() == "":
+				return nil, fmt.Errorf("document %d of %s has no metadata.name", document, name)
+			}
+		}
+		objects = append(objects, items...)
+	}
+
+	return objects, nil
+}
+
+// ObjectYAML returns an object as YAML without the fields the server manages, like the status, managed fields and
+// creation timestamp, so it can be edited or compared. The resourceVersion is kept if keepResourceVersion is true, so
+// updating the edited object fails if the object was changed in the meantime.
+// It returns an error if the object cannot be converted to YAML.
+func ObjectYAML(object *unstructured.Unstructured, keepResourceVersion bool) (string, error) {
+	if object == nil {
+		return "", nil
+	}
+	object = object.DeepCopy()
+	unstructured.RemoveNestedField(object.Object, "status")
+	for _, field := range []string{"managedFields", "creationTimestamp", "generation", "uid", "selfLink"} {
+		unstructured.RemoveNestedField(object.Object, "metadata", field)
+	}
+	if !keepResourceVersion {
+		unstructured.RemoveNestedField(object.Object, "metadata", "resourceVersion")
+	}
+	unstructured.RemoveNestedField(object.Object, "metadata", "annotations", "kubectl.kubernetes.io/last-applied-configuration")
+	if len(object.GetAnnotations()) == 0 {
+		unstructured.RemoveNestedField(object.Object, "metadata", "annotations")
+	}
+	data, err := yaml.Marshal(object.Object)
+	if err != nil {
+		return "", fmt.Errorf("unable to convert %s %s to YAML: %w", object.GetKind(), object.GetName(), err)
+	}
+
+	return string(data), nil
+}
+
+// ResourceForObject resolves the resource type of an object from its apiVersion and kind, using the cached
+// discovery data. It returns an error if the server does not have the kind.
+func (c *Client) ResourceForObject(ctx context.Context, object *unstructured.Unstructured) (APIResource, error) {
+	gvk := object.GroupVersionKind()
+	mapping, err := withTimeout(ctx, c, func() (*meta.RESTMapping, error) {
+		return c.restMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	})
+	if err != nil {
+		return APIResource{}, fmt.Errorf("the server doesn't have a kind %s in %s: %w", gvk.Kind, gvk.GroupVersion(), err)
+	}
+
+	return APIResource{
+		Group:      mapping.Resource.Group,
+		Version:    mapping.Resource.Version,
+		Resource:   mapping.Resource.Resource,
+		Kind:       gvk.Kind,
+		Namespaced: mapping.Scope.Name() == meta.RESTScopeNameNamespace,
+	}, nil
+}
+
+// WaitForResourceForObject resolves the resource type of an object like ResourceForObject, and waits up to timeout
+// until the server serves its kind, e.g. the kind of a CustomResourceDefinition that was just created. The cached
+// discovery data is refreshed while waiting.
+// It returns an error if the server does not serve the kind within the timeout or the context is cancelled.
+func (c *Client) WaitForResourceForObject(ctx context.Context, object *unstructured.Unstructured, timeout time.Duration) (APIResource, error) {
+	var resource APIResource
+	var err error
+	pollErr := wait.PollUntilContextTimeout(ctx, resourcePollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		c.discovery.Invalidate()
+		resource, err = c.ResourceForObject(ctx, object)
+		return err == nil, nil
+	})
+	if pollErr != nil && err != nil {
+		return APIResource{}, fmt.Errorf("unable to resolve the kind %s: %w, last error: %v", object.GetKind(), pollErr, err)
+	}
+	if pollErr != nil {
+		return APIResource{}, fmt.Errorf("unable to resolve the kind %s: %w", object.GetKind(), pollErr)
+	}
+
+	return resource, nil
+}
+
+// ApplyObject applies an object of a resource type with server-side apply, as field manager wimkube. Unknown and
+// duplicate fields are rejected. With dryRun, the server validates and returns the result without persisting it.
+// With force, fields owned by other field managers are taken over instead of reported as conflicts.
+// It returns the object as the server stores it, and an error if the object cannot be applied.
+func (c *Client) ApplyObject(ctx context.Context, resource APIResource, object *unstructured.Unstructured, dryRun, force bool) (*unstructured.Unstructured, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	client, err := c.resourceInterface(resource, object.GetNamespace())
+	if err != nil {
+		return nil, fmt.Errorf("unable to apply %s %s: %w", resource, object.GetName(), err)
+	}
+	data, err := json.Marshal(object.Object)
+	if err != nil {
+		return nil, fmt.Errorf("unable to apply %s %s: %w", resource, object.GetName(), err)
+	}
+	options := metav1.PatchOptions{FieldManager: FieldManager, Force: &force, FieldValidation: "Strict"}
+	if dryRun {
+		options.DryRun = []string{metav1.DryRunAll}
+	}
+	applied, err := client.Patch(ctx, object.GetName(), types.ApplyPatchType, data, options)
+	if err != nil {
+		return nil, fmt.Errorf("unable to apply %s %s: %w", resource, object.GetName(), err)
+	}
+
+	return applied, nil
+}
+
+// UpdateObject replaces an object of a resource type with the object, like kubectl edit does, so fields that are not
+// in the object are removed. The resourceVersion of the object must still be the one of the live object, so changes
+// made in the meantime are not overwritten. Unknown and duplicate fields are rejected. With dryRun, the server
+// validates and returns the result without persisting it.
+// It returns the object as the server stores it, and an error if the object cannot be updated.
+func (c *Client) UpdateObject(ctx context.Context, resource APIResource, object *unstructured.Unstructured, dryRun bool) (*unstructured.Unstructured, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	client, err := c.resourceInterface(resource, object.GetNamespace())
+	if err != nil {
+		return nil, fmt.Errorf("unable to update %s %s: %w", resource, object.GetName(), err)
+	}
+	options := metav1.UpdateOptions{FieldManager: FieldManager, FieldValidation: "Strict"}
+	if dryRun {
+		options.DryRun = []string{metav1.DryRunAll}
+	}
+	updated, err := client.Update(ctx, object, options)
+	if err != nil {
+		return nil, fmt.Errorf("unable to update %s %s: %w", resource, object.GetName(), err)
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,203 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestReadManifests(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"app.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+---
+# Empty documents are skipped.
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: api
+`,
+		"list.json":  `{"apiVersion": "v1", "kind": "List", "items": [{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "settings"}}]}`,
+		"README.txt": "not a manifest",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	objects, err := ReadManifests(dir)
+	if err != nil {
+		t.Fatalf("ReadManifests() error = %v", err)
+	}
+	var got []string
+	for _, object := range objects {
+		got = append(got, object.GetKind()+"/"+object.GetName())
+	}
+	if want := []string{"Deployment/api", "Service/api", "ConfigMap/settings"}; !slices.Equal(got, want) {
+		t.Errorf("ReadManifests() = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		manifest string
+		wantErr  string
+	}{
+		{manifest: "kind: ConfigMap\nmetadata:\n  name: settings\n", wantErr: "document 1 of test has no apiVersion"},
+		{manifest: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n---\napiVersion: v1\nkind: ConfigMap\n", wantErr: "document 2 of test has no metadata.name"},
+		{manifest: "apiVersion: v1\nkind: [", wantErr: "unable to parse document 1 of test"},
+	}
+	for _, tt := range tests {
+		if _, err := ParseManifests("test", []byte(tt.manifest)); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("ParseManifests(%q) error = %v, want %q", tt.manifest, err, tt.wantErr)
+		}
+	}
+}
+
+func TestObjectYAML(t *testing.T) {
+	object := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]any{
+			"name":              "settings",
+			"namespace":         "payments",
+			"resourceVersion":   "42",
+			"uid":               "0c2e1a4e",
+			"creationTimestamp": "2026-01-01T00:00:00Z",
+			"managedFields":     []any{map[string]any{"manager": "kubectl"}},
+			"annotations":       map[string]any{"kubectl.kubernetes.io/last-applied-configuration": "{}"},
+		},
+		"data": map[string]any{"mode": "fast"},
+	}}
+
+	got, err := ObjectYAML(object, true)
+	if err != nil {
+		t.Fatalf("ObjectYAML() error = %v", err)
+	}
+	want := `apiVersion: v1
+data:
+  mode: fast
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: payments
+  resourceVersion: "42"
+`
+	if got != want {
+		t.Errorf("ObjectYAML() = %q, want %q", got, want)
+	}
+	if got, _ := ObjectYAML(object, false); strings.Contains(got, "resourceVersion") {
+		t.Errorf("ObjectYAML() without resource version = %q", got)
+	}
+}
+
+func TestApplyObject(t *testing.T) {
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme.Scheme)
+	var options []metav1.PatchOptions
+	dynamicClient.PrependReactor("patch", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchActionImpl)
+		if patch.GetPatchType() != types.ApplyPatchType {
+			t.Errorf("patch type = %s, want %s", patch.GetPatchType(), types.ApplyPatchType)
+		}
+		options = append(options, patch.PatchOptions)
+		object := &unstructured.Unstructured{}
+		return true, object, object.UnmarshalJSON(patch.GetPatch())
+	})
+	c := NewClientFromInterfaces(fake.NewClientset(), dynamicClient, time.Second)
+	resource := APIResource{Version: "v1", Resource: "configmaps", Kind: "ConfigMap", Namespaced: true}
+	object := &unstructured.Unstructured{}
+	object.SetAPIVersion("v1")
+	object.SetKind("ConfigMap")
+	object.SetNamespace("payments")
+	object.SetName("settings")
+
+	if _, err := c.ApplyObject(t.Context(), resource, object, true, false); err != nil {
+		t.Fatalf("ApplyObject(dry run) error = %v", err)
+	}
+	if _, err := c.ApplyObject(t.Context(), resource, object, false, true); err != nil {
+		t.Fatalf("ApplyObject() error = %v", err)
+	}
+	if len(options) != 2 {
+		t.Fatalf("ApplyObject() sent %d patches, want 2", len(options))
+	}
+	if got := options[0]; !slices.Equal(got.DryRun, []string{metav1.DryRunAll}) || *got.Force || got.FieldManager != FieldManager || got.FieldValidation != "Strict" {
+		t.Errorf("dry run patch options = %+v", got)
+	}
+	if got := options[1]; len(got.DryRun) != 0 || !*got.Force {
+		t.Errorf("patch options = %+v, want a forced apply that is not a dry run", got)
+	}
+}
+
+func TestUpdateObject(t *testing.T) {
+	live := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]any{"namespace": "payments", "name": "settings"},
+		"data":       map[string]any{"mode": "fast", "level": "2"},
+	}}
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme.Scheme, live)
+	var options []metav1.UpdateOptions
+	dynamicClient.PrependReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		update := action.(k8stesting.UpdateActionImpl)
+		options = append(options, update.UpdateOptions)
+		return false, nil, nil
+	})
+	c := NewClientFromInterfaces(fake.NewClientset(), dynamicClient, time.Second)
+	resource := APIResource{Version: "v1", Resource: "configmaps", Kind: "ConfigMap", Namespaced: true}
+	edited := live.DeepCopy()
+	unstructured.RemoveNestedField(edited.Object, "data", "level")
+
+	updated, err := c.UpdateObject(t.Context(), resource, edited, false)
+	if err != nil {
+		t.Fatalf("UpdateObject() error = %v", err)
+	}
+	// Unlike server-side apply, an update removes the fields that are not in the object.
+	if _, found, _ := unstructured.NestedString(updated.Object, "data", "level"); found {
+		t.Errorf("UpdateObject() = %v, want data.level removed", updated.Object["data"])
+	}
+	if _, err := c.UpdateObject(t.Context(), resource, edited, true); err != nil {
+		t.Fatalf("UpdateObject(dry run) error = %v", err)
+	}
+	if len(options) != 2 {
+		t.Fatalf("UpdateObject() sent %d updates, want 2", len(options))
+	}
+	if got := options[0]; len(got.DryRun) != 0 || got.FieldManager != FieldManager || got.FieldValidation != "Strict" {
+		t.Errorf("update options = %+v", got)
+	}
+	if got := options[1]; !slices.Equal(got.DryRun, []string{metav1.DryRunAll}) {
+		t.Errorf("update options = %+v, want a dry run", got)
+	}
+}
+
+func TestWaitForResourceForObject(t *testing.T) {
+	clientset := fake.NewClientset()
+	clientset.Resources = []*metav1.APIResourceList{{GroupVersion: "v1"}}
+	c := NewClientFromInterfaces(clientset, dynamicfake.NewSimpleDynamicClient(scheme.Scheme), time.Second)
+	object := &unstructured.Unstructured{}
+	object.SetAPIVersion("example.com/v1")
+	object.SetKind("Gadget")
+	object.SetName("blue")
+
+	// The error reports that the wait timed out, not only the last lookup that failed.
+	_, err := c.WaitForResourceForObject(t.Context(), object, 10*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), `no matches for kind "Gadget"`) {
+		t.Errorf("WaitForResourceForObject() error = %v, want a timeout with the last error", err)
+	}
+}
@@ -0,0 +1,401 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Credential types of a kubeconfig user.
+const (
+	CredentialClientCertificate = "client certificate"
+	CredentialToken             = "token"
+	CredentialTokenFile         = "token file"
+	CredentialExecPlugin        = "exec plugin"
+	CredentialAuthProvider      = "auth provider"
+	CredentialBasicAuth         = "basic auth"
+	CredentialNone              = "none"
+)
+
+// UserInfo is the user the API server authenticated the credentials of a context as.
+type UserInfo struct {
+	Username string              `json:"username"`
+	UID      string              `json:"uid,omitempty"`
+	Groups   []string            `json:"groups"`
+	Extra    map[string][]string `json:"extra,omitempty"`
+}
+
+// Credentials describes how the user of a context authenticates and when its credentials expire.
+type Credentials struct {
+	Context string `json:"context"`
+	User    string `json:"user"`
+	Type    string `json:"type"`
+	// Command is the command of an exec plugin, Provider the name of an auth provider.
+	Command     string   `json:"command,omitempty"`
+	Args        []string `json:"args,omitempty"`
+	InstallHint string   `json:"installHint,omitempty"`
+	Provider    string   `json:"provider,omitempty"`
+	// Issuer is the issuer URL of OIDC credentials, from an oidc auth provider or the arguments of an exec plugin.
+	Issuer string `json:"issuer,omitempty"`
+	// Expiry is the zero time if the credentials do not expire or their expiry is unknown.
+	Expiry time.Time `json:"expiry,omitzero"`
+	// CAExpiry is the expiry of the certificate authority of the cluster, if the kubeconfig contains it.
+	CAExpiry time.Time `json:"caExpiry,omitzero"`
+}
+
+// ClusterInfo describes the cluster of a context as it is in the kubeconfig file.
+type ClusterInfo struct {
+	Context   string `json:"context"`
+	Cluster   string `json:"cluster"`
+	Server    string `json:"server"`
+	Namespace string `json:"namespace"`
+	// CAFingerprint is the SHA-256 fingerprint of the certificate authority, if the kubeconfig contains it.
+	CAFingerprint         string    `json:"caFingerprint,omitempty"`
+	CASubject             string    `json:"caSubject,omitempty"`
+	CAExpiry              time.Time `json:"caExpiry,omitzero"`
+	InsecureSkipTLSVerify bool      `json:"insecureSkipTLSVerify,omitempty"`
+	ProxyURL              string    `json:"proxyURL,omitempty"`
+}
+
+// execCredential is the part of the ExecCredential written by an exec plugin that is needed to find the expiry.
+// It is the same for the v1 and v1beta1 API versions.
+type execCredential struct {
+	Status struct {
+		ExpirationTimestamp   *time.Time `json:"expirationTimestamp"`
+		Token                 string     `json:"token"`
+		ClientCertificateData string     `json:"clientCertificateData"`
+	} `json:"status"`
+}
+
+// WhoAmI asks the API server which user it authenticates the credentials of the client as, using a SelfSubjectReview.
+// It returns an error if the review cannot be created, e.g. because the credentials are rejected.
+func (c *Client) WhoAmI(ctx context.Context) (*UserInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	review, err := c.client.AuthenticationV1().SelfSubjectReviews().Create(ctx, &authenticationv1.SelfSubjectReview{}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create a self subject review: %w", err)
+	}
+	user := review.Status.UserInfo
+	info := &UserInfo{
+		Username: user.Username,
+		UID:      user.UID,
+		Groups:   user.Groups,
+	}
+	if len(user.Extra) > 0 {
+		info.Extra = make(map[string][]string, len(user.Extra))
+		for key, values := range user.Extra {
+			info.Extra[key] = values
+		}
+	}
+
+	return info, nil
+}
+
+// ServerTime returns the time of the API server, taken from the Date header of a request for its version.
+// It returns an error if the client has no REST config or the server cannot be reached.
+func (c *Client) ServerTime(ctx context.Context) (time.Time, error) {
+	if c.config == nil {
+		return time.Time{}, fmt.Errorf("unable to get the server time: the client has no REST config")
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	httpClient, err := rest.HTTPClientFor(c.config)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to create an HTTP client: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.config.Host, "/")+"/version", nil)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to get the server time: %w", err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to get the server time: %w", err)
+	}
+	defer resp.Body.Close()
+	serverTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to get the server time: the response has no valid Date header")
+	}
+
+	return serverTime, nil
+}
+
+// GetCredentials describes the credentials of the user of the specified context as they are in the kubeconfig file.
+// The expiry of exec plugin credentials is only known after running the plugin with RunExecPlugin.
+// It returns an error if the context or its user do not exist.
+func (k *KubeConfig) GetCredentials(contextName string) (*Credentials, error) {
+	context, exists := k.config.Contexts[contextName]
+	if !exists {
+		return nil, fmt.Errorf("context '%s' does not exist", contextName)
+	}
+	user, exists := k.config.AuthInfos[context.AuthInfo]
+	if !exists {
+		return nil, fmt.Errorf("user '%s' of context '%s' does not exist", context.AuthInfo, contextName)
+	}
+
+	creds := &Credentials{Context: contextName, User: context.AuthInfo}
+	switch {
+	case user.Exec != nil:
+		creds.Type = CredentialExecPlugin
+		creds.Command = user.Exec.Command
+		creds.Args = user.Exec.Args
+		creds.InstallHint = user.Exec.InstallHint
+		creds.Issuer = oidcIssuer(user.Exec.Args)
+	case user.AuthProvider != nil:
+		creds.Type = CredentialAuthProvider
+		creds.Provider = user.AuthProvider.Name
+		creds.Issuer = user.AuthProvider.Config["idp-issuer-url"]
+		creds.Expiry = authProviderExpiry(user.AuthProvider)
+	case len(user.ClientCertificateData) > 0 || user.ClientCertificate != "":
+		creds.Type = CredentialClientCertificate
+		data, err := readData(user.ClientCertificateData, user.ClientCertificate, user.LocationOfOrigin)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the client certificate of user '%s': %w", context.AuthInfo, err)
+		}
+		creds.Expiry, err = certificateExpiry(data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the client certificate of user '%s': %w", context.AuthInfo, err)
+		}
+	case user.Token != "":
+		creds.Type = CredentialToken
+		creds.Expiry = tokenExpiry(user.Token)
+	case user.TokenFile != "":
+		creds.Type = CredentialTokenFile
+		data, err := os.ReadFile(resolvePath(user.TokenFile, user.LocationOfOrigin))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the token file of user '%s': %w", context.AuthInfo, err)
+		}
+		creds.Expiry = tokenExpiry(strings.TrimSpace(string(data)))
+	case user.Username != "":
+		creds.Type = CredentialBasicAuth
+	default:
+		creds.Type = CredentialNone
+	}
+
+	if cluster, exists := k.config.Clusters[context.Cluster]; exists {
+		data, err := readData(cluster.CertificateAuthorityData, cluster.CertificateAuthority, cluster.LocationOfOrigin)
+		if err == nil && len(data) > 0 {
+			creds.CAExpiry, _ = certificateExpiry(data)
+		}
+	}
+
+	return creds, nil
+}
+
+// GetClusterInfo describes the cluster of the specified context as it is in the kubeconfig file.
+// It returns an error if the context or its cluster do not exist, or the certificate authority cannot be read.
+func (k *KubeConfig) GetClusterInfo(contextName string) (*ClusterInfo, error) {
+	context, exists := k.config.Contexts[contextName]
+	if !exists {
+		return nil, fmt.Errorf("context '%s' does not exist", contextName)
+	}
+	cluster, exists := k.config.Clusters[context.Cluster]
+	if !exists {
+		return nil, fmt.Errorf("cluster '%s' of context '%s' does not exist", context.Cluster, contextName)
+	}
+
+	info := &ClusterInfo{
+		Context:               contextName,
+		Cluster:               context.Cluster,
+		Server:                cluster.Server,
+		Namespace:             context.Namespace,
+		InsecureSkipTLSVerify: cluster.InsecureSkipTLSVerify,
+		ProxyURL:              cluster.ProxyURL,
+	}
+	if info.Namespace == "" {
+		info.Namespace = "default"
+	}
+	data, err := readData(cluster.CertificateAuthorityData, cluster.CertificateAuthority, cluster.LocationOfOrigin)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the certificate authority of cluster '%s': %w", context.Cluster, err)
+	}
+	if len(data) > 0 {
+		cert, err := parseCertificate(data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the certificate authority of cluster '%s': %w", context.Cluster, err)
+		}
+		info.CAFingerprint = fingerprint(cert)
+		info.CASubject = cert.Subject.String()
+		info.CAExpiry = cert.NotAfter
+	}
+
+	return info, nil
+}
+
+// RunExecPlugin runs the exec plugin of the user of the specified context, like client-go does before a request,
+// and returns the expiry of the credentials it writes. The expiry is the zero time if the plugin does not report it.
+// It returns an error if the user has no exec plugin, the plugin cannot be found or it fails.
+func (k *KubeConfig) RunExecPlugin(ctx context.Context, contextName string) (time.Time, error) {
+	context, exists := k.config.Contexts[contextName]
+	if !exists {
+		return time.Time{}, fmt.Errorf("context '%s' does not exist", contextName)
+	}
+	user, exists := k.config.AuthInfos[context.AuthInfo]
+	if !exists || user.Exec == nil {
+		return time.Time{}, fmt.Errorf("user '%s' of context '%s' has no exec plugin", context.AuthInfo, contextName)
+	}
+
+	command := user.Exec.Command
+	if strings.ContainsRune(command, filepath.Separator) {
+		// Like client-go, a command with a path separator is relative to the kubeconfig file.
+		command = resolvePath(command, user.LocationOfOrigin)
+	}
+	path, err := exec.LookPath(command)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("exec plugin %s not found: %w", user.Exec.Command, err)
+	}
+	cmd := exec.CommandContext(ctx, path, user.Exec.Args...)
+	cmd.Env = os.Environ()
+	for _, env := range user.Exec.Env {
+		cmd.Env = append(cmd.Env, env.Name+"="+env.Value)
+	}
+	execInfo := fmt.Sprintf(`{"apiVersion":%q,"kind":"ExecCredential","spec":{"interactive":false}}`, user.Exec.APIVersion)
+	cmd.Env = append(cmd.Env, "KUBERNETES_EXEC_INFO="+execInfo)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return time.Time{}, fmt.Errorf("exec plugin %s failed: %w: %s", user.Exec.Command, err, msg)
+		}
+		return time.Time{}, fmt.Errorf("exec plugin %s failed: %w", user.Exec.Command, err)
+	}
+
+	var credential execCredential
+	if err := json.Unmarshal(stdout.Bytes(), &credential); err != nil {
+		return time.Time{}, fmt.Errorf("exec plugin %s wrote an invalid ExecCredential: %w", user.Exec.Command, err)
+	}
+	status := credential.Status
+	switch {
+	case status.ExpirationTimestamp != nil:
+		return *status.ExpirationTimestamp, nil
+	case status.Token != "":
+		return tokenExpiry(status.Token), nil
+	case status.ClientCertificateData != "":
+		expiry, _ := certificateExpiry([]byte(status.ClientCertificateData))
+		return expiry, nil
+	}
+
+	return time.Time{}, nil
+}
+
+// tokenExpiry returns the expiry of a bearer token that is a JWT, or the zero time for other tokens.
+// The token is not verified, the expiry is only informative.
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(claims.Exp, 0)
+}
+
+// authProviderExpiry returns the expiry of the cached credentials of an auth provider, which the oidc provider keeps
+// as an id token and other providers in an expiry field.
+func authProviderExpiry(provider *api.AuthProviderConfig) time.Time {
+	if token := provider.Config["id-token"]; token != "" {
+		return tokenExpiry(token)
+	}
+	for _, key := range []string{"expiry", "expires-on"} {
+		if value := provider.Config[key]; value != "" {
+			if expiry, err := time.Parse(time.RFC3339, value); err == nil {
+				return expiry
+			}
+		}
+	}
+
+	return time.Time{}
+}
+
+// oidcIssuer returns the issuer URL in the arguments of an exec plugin, like kubelogin and kubectl oidc-login take it.
+func oidcIssuer(args []string) string {
+	for i, arg := range args {
+		if issuer, found := strings.CutPrefix(arg, "--oidc-issuer-url="); found {
+			return issuer
+		}
+		if arg == "--oidc-issuer-url" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+
+	return ""
+}
+
+// certificateExpiry returns the expiry of the first certificate in PEM encoded data.
+func certificateExpiry(data []byte) (time.Time, error) {
+	cert, err := parseCertificate(data)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return cert.NotAfter, nil
+}
+
+// parseCertificate parses the first certificate in PEM encoded data.
+func parseCertificate(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// fingerprint returns the SHA-256 fingerprint of a certificate as colon-separated hex bytes, like openssl prints it.
+func fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	hex := make([]string, len(sum))
+	for i, b := range sum {
+		hex[i] = fmt.Sprintf("%02X", b)
+	}
+
+	return strings.Join(hex, ":")
+}
+
+// readData returns data if it is set and otherwise reads the file, which is relative to the kubeconfig it is in.
+func readData(data []byte, file, origin string) ([]byte, error) {
+	if len(data) > 0 || file == "" {
+		return data, nil
+	}
+
+	return os.ReadFile(resolvePath(file, origin))
+}
+
+// resolvePath makes a path in a kubeconfig file absolute, relative paths being relative to the kubeconfig file.
+func resolvePath(path, origin string) string {
+	if path == "" || filepath.IsAbs(path) || origin == "" {
+		return path
+	}
+
+	return filepath.Join(filepath.Dir(origin), path)
+}
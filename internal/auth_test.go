@@ -0,0 +1,241 @@
+package internal
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// testCertificate returns a PEM encoded self-signed certificate that expires at notAfter.
+func testCertificate(t *testing.T, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "wimkube-test"},
+		NotBefore:    notAfter.Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+// testToken returns an unsigned JWT with the claims.
+func testToken(claims string) string {
+	encode := base64.RawURLEncoding.EncodeToString
+
+	return encode([]byte(`{"alg":"none"}`)) + "." + encode([]byte(claims)) + ".signature"
+}
+
+func TestTokenExpiry(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+		want  time.Time
+	}{
+		{name: "JWT with exp", token: testToken(`{"sub":"alice","exp":1792396800}`), want: time.Unix(1792396800, 0)},
+		{name: "JWT without exp", token: testToken(`{"sub":"alice"}`)},
+		{name: "opaque token", token: "abcdef0123456789"},
+		{name: "invalid payload", token: "a.!!!.c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tokenExpiry(tt.token); !got.Equal(tt.want) {
+				t.Errorf("tokenExpiry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetCredentials(t *testing.T) {
+	dir := t.TempDir()
+	notAfter := time.Now().Add(72 * time.Hour).Truncate(time.Second).UTC()
+	if err := os.WriteFile(filepath.Join(dir, "client.crt"), testCertificate(t, notAfter), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "token"), []byte(testToken(`{"exp":1792396800}`)+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	kubeconfig := filepath.Join(dir, "config")
+	// The client certificate and token file are relative to the kubeconfig file.
+	config := fmt.Sprintf(`apiVersion: v1
+kind: Config
+current-context: cert
+clusters:
+- name: test
+  cluster:
+    server: https://127.0.0.1:6443
+    certificate-authority-data: %s
+contexts:
+- {name: cert, context: {cluster: test, user: cert}}
+- {name: token-file, context: {cluster: test, user: token-file}}
+- {name: exec, context: {cluster: test, user: exec}}
+- {name: oidc, context: {cluster: test, user: oidc}}
+- {name: anonymous, context: {cluster: test, user: anonymous}}
+- {name: no-user, context: {cluster: test, user: missing}}
+users:
+- {name: cert, user: {client-certificate: client.crt, client-key: client.key}}
+- {name: token-file, user: {tokenFile: token}}
+- {name: exec, user: {exec: {apiVersion: client.authentication.k8s.io/v1, command: kubelogin, args: [get-token], installHint: install kubelogin}}}
+- {name: oidc, user: {auth-provider: {name: oidc, config: {id-token: %s}}}}
+- {name: anonymous, user: {}}
+`, base64.StdEncoding.EncodeToString(testCertificate(t, notAfter.Add(24*time.Hour))), testToken(`{"exp":1792396800}`))
+	if err := os.WriteFile(kubeconfig, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	k, err := NewKubeConfig(kubeconfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		context     string
+		wantType    string
+		wantCommand string
+		wantExpiry  time.Time
+		wantErr     bool
+	}{
+		{context: "cert", wantType: CredentialClientCertificate, wantExpiry: notAfter},
+		{context: "token-file", wantType: CredentialTokenFile, wantExpiry: time.Unix(1792396800, 0)},
+		{context: "exec", wantType: CredentialExecPlugin, wantCommand: "kubelogin"},
+		{context: "oidc", wantType: CredentialAuthProvider, wantExpiry: time.Unix(1792396800, 0)},
+		{context: "anonymous", wantType: CredentialNone},
+		{context: "no-user", wantErr: true},
+		{context: "missing", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.context, func(t *testing.T) {
+			got, err := k.GetCredentials(tt.context)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetCredentials() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Type != tt.wantType || got.Command != tt.wantCommand || !got.Expiry.Equal(tt.wantExpiry) {
+				t.Errorf("GetCredentials() = %s %q expiring %v, want %s %q expiring %v",
+					got.Type, got.Command, got.Expiry, tt.wantType, tt.wantCommand, tt.wantExpiry)
+			}
+			if want := notAfter.Add(24 * time.Hour); !got.CAExpiry.Equal(want) {
+				t.Errorf("GetCredentials() CA expiry = %v, want %v", got.CAExpiry, want)
+			}
+		})
+	}
+}
+
+func TestGetClusterInfo(t *testing.T) {
+	dir := t.TempDir()
+	notAfter := time.Now().Add(365 * 24 * time.Hour).Truncate(time.Second).UTC()
+	ca := testCertificate(t, notAfter)
+	if err := os.WriteFile(filepath.Join(dir, "ca.crt"), ca, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	kubeconfig := filepath.Join(dir, "config")
+	config := `apiVersion: v1
+kind: Config
+current-context: prod
+clusters:
+- {name: prod, cluster: {server: "https://prod.example.com", certificate-authority: ca.crt}}
+- {name: dev, cluster: {server: "https://dev.example.com", insecure-skip-tls-verify: true}}
+contexts:
+- {name: prod, context: {cluster: prod, user: prod, namespace: payments}}
+- {name: dev, context: {cluster: dev, user: dev}}
+- {name: no-cluster, context: {cluster: missing, user: dev}}
+users:
+- {name: prod, user: {token: prod}}
+- {name: dev, user: {token: dev}}
+`
+	if err := os.WriteFile(kubeconfig, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	k, err := NewKubeConfig(kubeconfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := k.GetClusterInfo("prod")
+	if err != nil {
+		t.Fatalf("GetClusterInfo(prod) error = %v", err)
+	}
+	block, _ := pem.Decode(ca)
+	sum := sha256.Sum256(block.Bytes)
+	if want := strings.ToUpper(hex.EncodeToString(sum[:])); strings.ReplaceAll(got.CAFingerprint, ":", "") != want {
+		t.Errorf("GetClusterInfo(prod) CA fingerprint = %s, want the SHA-256 of the certificate %s", got.CAFingerprint, want)
+	}
+	if got.Server != "https://prod.example.com" || got.Namespace != "payments" || !got.CAExpiry.Equal(notAfter) || got.CASubject != "CN=wimkube-test" {
+		t.Errorf("GetClusterInfo(prod) = %+v", got)
+	}
+
+	got, err = k.GetClusterInfo("dev")
+	if err != nil {
+		t.Fatalf("GetClusterInfo(dev) error = %v", err)
+	}
+	if !got.InsecureSkipTLSVerify || got.CAFingerprint != "" || got.Namespace != "default" {
+		t.Errorf("GetClusterInfo(dev) = %+v, want an insecure cluster without CA in namespace default", got)
+	}
+
+	if _, err := k.GetClusterInfo("no-cluster"); err == nil {
+		t.Errorf("GetClusterInfo(no-cluster) error = nil, want an error for the missing cluster")
+	}
+}
+
+func TestOIDCIssuer(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"oidc-login", "get-token", "--oidc-issuer-url=https://dex.example.com"}, want: "https://dex.example.com"},
+		{args: []string{"get-token", "--oidc-issuer-url", "https://login.example.com", "--oidc-client-id", "k8s"}, want: "https://login.example.com"},
+		{args: []string{"eks", "get-token", "--cluster-name", "prod"}},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			if got := oidcIssuer(tt.args); got != tt.want {
+				t.Errorf("oidcIssuer() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWhoAmI(t *testing.T) {
+	clientset := fake.NewClientset()
+	clientset.PrependReactor("create", "selfsubjectreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := &authenticationv1.SelfSubjectReview{}
+		review.Status.UserInfo = authenticationv1.UserInfo{Username: "alice", Groups: []string{"developers", "system:authenticated"}}
+		return true, review, nil
+	})
+
+	got, err := NewClientFromInterface(clientset, time.Second).WhoAmI(t.Context())
+	if err != nil {
+		t.Fatalf("WhoAmI() error = %v", err)
+	}
+	if got.Username != "alice" || !slices.Equal(got.Groups, []string{"developers", "system:authenticated"}) {
+		t.Errorf("WhoAmI() = %+v, want alice in developers and system:authenticated", got)
+	}
+}
@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// unsafeFileChars matches the characters that are replaced in cache file names.
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9.-]`)
+
+// Cache stores API data on disk for a limited time, so interactive pickers do not have to wait for the cluster.
+type Cache struct {
+	dir string
+	ttl time.Duration
+}
+
+// NewCache creates a cache in the specified directory whose entries expire after ttl.
+func NewCache(dir string, ttl time.Duration) *Cache {
+	return &Cache{
+		dir: dir,
+		ttl: ttl,
+	}
+}
+
+// Load reads the entry stored under key into v.
+// It reports whether the entry exists, has not expired and could be decoded.
+func (c *Cache) Load(key string, v any) bool {
+	path := c.path(key)
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) > c.ttl {
+		return false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	return json.Unmarshal(data, v) == nil
+}
+
+// Store writes v to the cache under key.
+// It returns an error if the cache directory or the entry cannot be written.
+func (c *Cache) Store(key string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("unable to encode cache entry %s: %w", key, err)
+	}
+	if err := os.MkdirAll(c.dir, 0o750); err != nil {
+		return fmt.Errorf("unable to create cache directory %s: %w", c.dir, err)
+	}
+	// Write to a temporary file first, so concurrent invocations never read a partially written entry.
+	tmp, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("unable to write cache entry %s: %w", key, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("unable to write cache entry %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("unable to write cache entry %s: %w", key, err)
+	}
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		return fmt.Errorf("unable to write cache entry %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// Delete removes the entry stored under key. A missing entry is not an error.
+func (c *Cache) Delete(key string) error {
+	err := os.Remove(c.path(key))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to delete cache entry %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// path returns the file of the entry stored under key.
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, cacheFileName(key)+".json")
+}
+
+// cacheFileName turns a key, e.g. a server URL, into a name that can be used as a file or directory name.
+func cacheFileName(key string) string {
+	return unsafeFileChars.ReplaceAllString(key, "_")
+}
@@ -0,0 +1,102 @@
+package internal
+
+import (
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+func TestCache(t *testing.T) {
+	tests := []struct {
+		name   string
+		ttl    time.Duration
+		age    time.Duration
+		stored bool
+		want   bool
+	}{
+		{name: "fresh entry", ttl: time.Minute, stored: true, want: true},
+		{name: "expired entry", ttl: time.Minute, age: 2 * time.Minute, stored: true, want: false},
+		{name: "missing entry", ttl: time.Minute, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := NewCache(t.TempDir(), tt.ttl)
+			key := "namespaces-https://10.0.0.1:6443"
+			if tt.stored {
+				if err := cache.Store(key, []string{"default", "payments"}); err != nil {
+					t.Fatalf("Store() error = %v", err)
+				}
+				modTime := time.Now().Add(-tt.age)
+				if err := os.Chtimes(cache.path(key), modTime, modTime); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var got []string
+			if ok := cache.Load(key, &got); ok != tt.want {
+				t.Fatalf("Load() = %v, want %v", ok, tt.want)
+			}
+			if tt.want && !slices.Equal(got, []string{"default", "payments"}) {
+				t.Errorf("Load() loaded %v", got)
+			}
+		})
+	}
+}
+
+func TestGetCachedNamespaces(t *testing.T) {
+	clientset := fake.NewClientset(namespace("default"), namespace("payments"))
+	c := NewClientFromInterface(clientset, time.Second)
+	c.config = &rest.Config{Host: "https://10.0.0.1:6443"}
+	c.cache = NewCache(t.TempDir(), time.Minute)
+
+	if _, err := c.GetCachedNamespaces(t.Context()); err != nil {
+		t.Fatalf("GetCachedNamespaces() error = %v", err)
+	}
+	// The second call must be served from the cache, so a namespace created in between is not listed yet.
+	if err := clientset.Tracker().Add(namespace("orders")); err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.GetCachedNamespaces(t.Context())
+	if err != nil {
+		t.Fatalf("GetCachedNamespaces() error = %v", err)
+	}
+	if want := []string{"default", "payments"}; !slices.Equal(got, want) {
+		t.Errorf("GetCachedNamespaces() = %v, want %v", got, want)
+	}
+	// Listing the namespaces refreshes the cache.
+	if _, err := c.GetNamespaces(t.Context()); err != nil {
+		t.Fatalf("GetNamespaces() error = %v", err)
+	}
+	got, err = c.GetCachedNamespaces(t.Context())
+	if err != nil {
+		t.Fatalf("GetCachedNamespaces() error = %v", err)
+	}
+	if want := []string{"default", "orders", "payments"}; !slices.Equal(got, want) {
+		t.Errorf("GetCachedNamespaces() = %v, want %v", got, want)
+	}
+}
+
+func TestGetCachedNamespacesPerUser(t *testing.T) {
+	cache := NewCache(t.TempDir(), time.Minute)
+	admin := NewClientFromInterface(fake.NewClientset(namespace("default"), namespace("kube-system")), time.Second)
+	admin.config = &rest.Config{Host: "https://10.0.0.1:6443", BearerToken: "admin"}
+	admin.cache = cache
+	developer := NewClientFromInterface(fake.NewClientset(namespace("default")), time.Second)
+	developer.config = &rest.Config{Host: "https://10.0.0.1:6443", BearerToken: "developer"}
+	developer.cache = cache
+
+	if _, err := admin.GetCachedNamespaces(t.Context()); err != nil {
+		t.Fatalf("GetCachedNamespaces() error = %v", err)
+	}
+	// Another user of the same cluster must not get the namespaces cached for the admin.
+	got, err := developer.GetCachedNamespaces(t.Context())
+	if err != nil {
+		t.Fatalf("GetCachedNamespaces() error = %v", err)
+	}
+	if want := []string{"default"}; !slices.Equal(got, want) {
+		t.Errorf("GetCachedNamespaces() = %v, want %v", got, want)
+	}
+}
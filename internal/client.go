@@ -3,16 +3,26 @@ package internal
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
+	"path/filepath"
 	"time"
 
 	"golang.org/x/term"
 
-	"github.com/spf13/viper"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/disk"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -20,14 +30,23 @@ import (
 	"k8s.io/kubectl/pkg/scheme"
 )
 
+// DefaultRequestTimeout is the timeout of a single API request when no timeout is given to the client.
+const DefaultRequestTimeout = 30 * time.Second
+
 type Client struct {
-	client kubernetes.Interface
-	config *rest.Config
+	client    kubernetes.Interface
+	config    *rest.Config
+	discovery discovery.CachedDiscoveryInterface
+	dynamic   dynamic.Interface
+	cache     *Cache
+	timeout   time.Duration
 }
 
 // NewClient creates a new Kubernetes client using the specified kubeconfig file and context name.
+// Every API request of the client is limited to timeout, on top of the context passed to its methods.
+// If cache is not nil, discovery data and the namespaces of the cluster are cached on disk.
 // It returns an error if the kubeconfig file cannot be loaded or if the client cannot be created.
-func NewClient(kubeconfigFilename, contextName string) (*Client, error) {
+func NewClient(kubeconfigFilename, contextName string, timeout time.Duration, cache *Cache) (*Client, error) {
 	loadingRules := &clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfigFilename}
 	configOverrides := &clientcmd.ConfigOverrides{}
 	configOverrides.CurrentContext = contextName
@@ -41,16 +60,74 @@ func NewClient(kubeconfigFilename, contextName string) (*Client, error) {
 		return nil, fmt.Errorf("unable to create a client: %w", err)
 	}
 
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create a dynamic client: %w", err)
+	}
+
+	c := NewClientFromInterfaces(client, dynamicClient, timeout)
+	c.config = config
+	if cache != nil {
+		discoveryDir := filepath.Join(cache.dir, "discovery", cacheFileName(config.Host))
+		c.discovery, err = disk.NewCachedDiscoveryClientForConfig(config, discoveryDir, filepath.Join(cache.dir, "http"), cache.ttl)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create a discovery client: %w", err)
+		}
+		c.cache = cache
+	}
+
+	return c, nil
+}
+
+// NewClientFromInterface creates a new Kubernetes client on top of an existing clientset, e.g. a fake clientset in tests.
+// If timeout is not positive, DefaultRequestTimeout is used. Exec and attach need a REST config and are not available
+// on such a client, and neither are the methods working on any resource type.
+func NewClientFromInterface(client kubernetes.Interface, timeout time.Duration) *Client {
+	return NewClientFromInterfaces(client, nil, timeout)
+}
+
+// NewClientFromInterfaces creates a new Kubernetes client on top of an existing clientset and dynamic client, e.g.
+// fake clients in tests. If timeout is not positive, DefaultRequestTimeout is used.
+func NewClientFromInterfaces(client kubernetes.Interface, dynamicClient dynamic.Interface, timeout time.Duration) *Client {
+	if timeout <= 0 {
+		timeout = DefaultRequestTimeout
+	}
+
 	return &Client{
-		client: client,
-		config: config,
-	}, nil
+		client:    client,
+		discovery: memory.NewMemCacheClient(client.Discovery()),
+		dynamic:   dynamicClient,
+		timeout:   timeout,
+	}
+}
+
+// Discovery returns the discovery client of the cluster, which caches the API resources of the server.
+func (c *Client) Discovery() discovery.CachedDiscoveryInterface {
+	return c.discovery
+}
+
+// ServerVersion returns the Kubernetes version of the API server, e.g. v1.30.2.
+// It returns an error if the server cannot be reached.
+func (c *Client) ServerVersion(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	body, err := c.client.Discovery().RESTClient().Get().AbsPath("/version").DoRaw(ctx)
+	if err != nil {
+		return "", fmt.Errorf("unable to reach API server: %w", err)
+	}
+	var info version.Info
+	if err := json.Unmarshal(body, &info); err != nil {
+		return "", fmt.Errorf("unable to decode API server version: %w", err)
+	}
+
+	return info.GitVersion, nil
 }
 
 // GetNamespaces retrieves the list of namespaces in the Kubernetes cluster.
 // It returns a slice of namespace names and an error if the namespaces cannot be retrieved.
-func (c *Client) GetNamespaces() ([]string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(viper.GetInt("request-timeout"))*time.Second)
+func (c *Client) GetNamespaces(ctx context.Context) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
 	defer cancel()
 
 	namespaces, err := c.client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
@@ -61,14 +138,89 @@ func (c *Client) GetNamespaces() ([]string, error) {
 	for _, ns := range namespaces.Items {
 		out = append(out, ns.Name)
 	}
+	if c.cache != nil {
+		// A cache that cannot be written only makes the next picker slower.
+		_ = c.cache.Store(c.namespacesCacheKey(), out)
+	}
 
 	return out, nil
 }
 
+// GetCachedNamespaces returns the namespaces of the cluster from the cache and retrieves them if they are not cached
+// or the cached list has expired.
+// It returns a slice of namespace names and an error if the namespaces cannot be retrieved.
+func (c *Client) GetCachedNamespaces(ctx context.Context) ([]string, error) {
+	var namespaces []string
+	if c.cache != nil && c.cache.Load(c.namespacesCacheKey(), &namespaces) {
+		return namespaces, nil
+	}
+
+	return c.GetNamespaces(ctx)
+}
+
+// namespacesCacheKey returns the key under which the namespaces of the cluster are cached. Users of the same cluster
+// can be allowed to see different namespaces, so the key includes a hash of the credentials of the client.
+func (c *Client) namespacesCacheKey() string {
+	return "namespaces-" + c.config.Host + "-" + credentialsHash(c.config)
+}
+
+// credentialsHash returns a short hash of the credentials and impersonation of a REST config, which identifies the
+// user without storing the credentials themselves.
+func credentialsHash(config *rest.Config) string {
+	h := sha256.New()
+	fmt.Fprintln(h, config.Username, config.BearerToken, config.BearerTokenFile, config.CertFile, string(config.CertData))
+	if config.ExecProvider != nil {
+		fmt.Fprintln(h, config.ExecProvider.Command, config.ExecProvider.Args, config.ExecProvider.Env)
+	}
+	if config.AuthProvider != nil {
+		fmt.Fprintln(h, config.AuthProvider.Name, config.AuthProvider.Config)
+	}
+	fmt.Fprintln(h, config.Impersonate.UserName, config.Impersonate.UID, config.Impersonate.Groups)
+
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// NamespaceExists reports whether the specified namespace exists in the Kubernetes cluster.
+// It returns an error if the namespace cannot be retrieved for another reason than that it does not exist.
+func (c *Client) NamespaceExists(ctx context.Context, namespace string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	_, err := c.client.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("unable to get namespace %s: %w", namespace, err)
+	}
+
+	return true, nil
+}
+
+// IsForbidden reports whether err is caused by the user not being allowed to perform a request.
+func IsForbidden(err error) bool {
+	return apierrors.IsForbidden(err)
+}
+
+// IsUnauthorized reports whether err is caused by the API server rejecting the credentials of the user.
+func IsUnauthorized(err error) bool {
+	return apierrors.IsUnauthorized(err)
+}
+
+// IsNotFound reports whether err is caused by a resource, or an API, that does not exist.
+func IsNotFound(err error) bool {
+	return apierrors.IsNotFound(err)
+}
+
+// IsConflict reports whether err is caused by a resource that was changed since it was retrieved.
+func IsConflict(err error) bool {
+	return apierrors.IsConflict(err)
+}
+
 // GetPods retrieves the list of pods in the specified namespace.
 // It returns a slice of pod names and an error if the pods cannot be retrieved.
-func (c *Client) GetPods(namespace string) ([]string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(viper.GetInt("request-timeout"))*time.Second)
+func (c *Client) GetPods(ctx context.Context, namespace string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
 	defer cancel()
 
 	pods, err := c.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
@@ -85,8 +237,8 @@ func (c *Client) GetPods(namespace string) ([]string, error) {
 
 // GetContainers retrieves the list of container names in the specified pod and namespace.
 // It returns a slice of container names and an error if the pod cannot be retrieved.
-func (c *Client) GetContainers(namespace, podName string) ([]string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(viper.GetInt("request-timeout"))*time.Second)
+func (c *Client) GetContainers(ctx context.Context, namespace, podName string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
 	defer cancel()
 
 	pod, err := c.client.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
@@ -103,9 +255,13 @@ func (c *Client) GetContainers(namespace, podName string) ([]string, error) {
 
 // ExecInContainer executes a shell command in the specified container, pod, and namespace.
 // It returns an error if the command cannot be executed or if there is an issue with the terminal setup.
-func (c *Client) ExecInContainer(namespace, podName, containerName string) error {
-	ctx := context.Background()
+func (c *Client) ExecInContainer(ctx context.Context, namespace, podName, containerName string) error {
+	return c.ExecCommandInContainer(ctx, namespace, podName, containerName, []string{"/bin/sh", "-c", "command -v bash >/dev/null 2>&1 && bash || sh"})
+}
 
+// ExecCommandInContainer executes an interactive command in the specified container, pod, and namespace.
+// It returns an error if the command cannot be executed or if there is an issue with the terminal setup.
+func (c *Client) ExecCommandInContainer(ctx context.Context, namespace, podName, containerName string, command []string) error {
 	req := c.client.CoreV1().RESTClient().Post().
 		Resource("pods").
 		Name(podName).
@@ -113,14 +269,77 @@ func (c *Client) ExecInContainer(namespace, podName, containerName string) error
 		SubResource("exec").
 		VersionedParams(&corev1.PodExecOptions{
 			Container: containerName,
-			Command:   []string{"/bin/sh", "-c", "command -v bash >/dev/null 2>&1 && bash || sh"},
+			Command:   command,
 			Stdin:     true,
 			Stdout:    true,
 			Stderr:    true,
 			TTY:       true,
 		}, scheme.ParameterCodec)
 
-	executor, err := remotecommand.NewSPDYExecutor(c.config, "POST", req.URL())
+	return c.streamTerminal(ctx, req.URL())
+}
+
+// GetPodLogs retrieves the logs from a specific container in a pod.
+// It returns the logs as a string and an error if the logs cannot be retrieved.
+func (c *Client) GetPodLogs(ctx context.Context, namespace, podName, containerName string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	logOptions := &corev1.PodLogOptions{
+		Container: containerName,
+	}
+
+	req := c.client.CoreV1().Pods(namespace).GetLogs(podName, logOptions)
+	podLogs, err := req.Stream(ctx)
+	if err != nil {
+		return "", fmt.Errorf("unable to get pod logs for %s in namespace %s: %w", podName, namespace, err)
+	}
+	defer podLogs.Close()
+
+	buf := new(bytes.Buffer)
+	_, err = io.Copy(buf, podLogs)
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("unable to read pod logs: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// StreamPodLogs writes the logs of a specific container in a pod to out.
+// If follow is set, the logs are streamed until the container terminates; otherwise the request timeout applies.
+// It returns an error if the logs cannot be retrieved.
+func (c *Client) StreamPodLogs(ctx context.Context, namespace, podName, containerName string, follow bool, out io.Writer) error {
+	if !follow {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	logOptions := &corev1.PodLogOptions{
+		Container: containerName,
+		Follow:    follow,
+	}
+	podLogs, err := c.client.CoreV1().Pods(namespace).GetLogs(podName, logOptions).Stream(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get pod logs for %s in namespace %s: %w", podName, namespace, err)
+	}
+	defer podLogs.Close()
+
+	_, err = io.Copy(out, podLogs)
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("unable to read pod logs: %w", err)
+	}
+
+	return nil
+}
+
+// streamTerminal connects the local terminal to an exec or attach request with a TTY.
+// It returns an error if the stream cannot be created or if there is an issue with the terminal setup.
+func (c *Client) streamTerminal(ctx context.Context, u *url.URL) error {
+	if c.config == nil {
+		return fmt.Errorf("unable to create executor: the client has no REST config")
+	}
+	executor, err := remotecommand.NewSPDYExecutor(c.config, "POST", u)
 	if err != nil {
 		return fmt.Errorf("unable to create executor: %w", err)
 	}
@@ -150,32 +369,6 @@ func (c *Client) ExecInContainer(namespace, podName, containerName string) error
 	return nil
 }
 
-// GetPodLogs retrieves the logs from a specific container in a pod.
-// It returns the logs as a string and an error if the logs cannot be retrieved.
-func (c *Client) GetPodLogs(namespace, podName, containerName string) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(viper.GetInt("request-timeout"))*time.Second)
-	defer cancel()
-
-	logOptions := &corev1.PodLogOptions{
-		Container: containerName,
-	}
-
-	req := c.client.CoreV1().Pods(namespace).GetLogs(podName, logOptions)
-	podLogs, err := req.Stream(ctx)
-	if err != nil {
-		return "", fmt.Errorf("unable to get pod logs for %s in namespace %s: %w", podName, namespace, err)
-	}
-	defer podLogs.Close()
-
-	buf := new(bytes.Buffer)
-	_, err = io.Copy(buf, podLogs)
-	if err != nil && err != io.EOF {
-		return "", fmt.Errorf("unable to read pod logs: %w", err)
-	}
-
-	return buf.String(), nil
-}
-
 // setupTerminal puts the terminal into raw mode and returns the original terminal state.
 // It returns an error if the terminal cannot be put into raw mode.
 func setupTerminal(f *os.File) (*term.State, error) {
@@ -0,0 +1,170 @@
+package internal
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func namespace(name string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+}
+
+func pod(namespace, name string, containers ...string) *corev1.Pod {
+	p := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+	for _, container := range containers {
+		p.Spec.Containers = append(p.Spec.Containers, corev1.Container{Name: container})
+	}
+
+	return p
+}
+
+// forbidden makes every request with the verb on the resource fail as if the user lacks the RBAC permission.
+func forbidden(clientset *fake.Clientset, verb, resource string) {
+	clientset.PrependReactor(verb, resource, func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: resource}, "", errors.New("forbidden by test"))
+	})
+}
+
+func TestGetNamespaces(t *testing.T) {
+	tests := []struct {
+		name      string
+		objects   []runtime.Object
+		forbidden bool
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name: "no namespaces",
+			want: []string{},
+		},
+		{
+			name:    "several namespaces",
+			objects: []runtime.Object{namespace("default"), namespace("kube-system"), namespace("payments")},
+			want:    []string{"default", "kube-system", "payments"},
+		},
+		{
+			name:      "listing forbidden",
+			objects:   []runtime.Object{namespace("default")},
+			forbidden: true,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewClientset(tt.objects...)
+			if tt.forbidden {
+				forbidden(clientset, "list", "namespaces")
+			}
+			got, err := NewClientFromInterface(clientset, time.Second).GetNamespaces(t.Context())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetNamespaces() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !IsForbidden(err) {
+					t.Errorf("GetNamespaces() error = %v, want a forbidden error", err)
+				}
+				return
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("GetNamespaces() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNamespaceExists(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		forbidden bool
+		want      bool
+		wantErr   bool
+	}{
+		{name: "existing namespace", namespace: "payments", want: true},
+		{name: "missing namespace", namespace: "paymnts", want: false},
+		{name: "get forbidden", namespace: "payments", forbidden: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewClientset(namespace("default"), namespace("payments"))
+			if tt.forbidden {
+				forbidden(clientset, "get", "namespaces")
+			}
+			got, err := NewClientFromInterface(clientset, time.Second).NamespaceExists(t.Context(), tt.namespace)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NamespaceExists() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NamespaceExists() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPods(t *testing.T) {
+	objects := []runtime.Object{
+		pod("default", "web-1", "web"),
+		pod("default", "web-2", "web"),
+		pod("payments", "api-1", "api"),
+	}
+	tests := []struct {
+		name      string
+		namespace string
+		want      []string
+	}{
+		{name: "pods of one namespace", namespace: "default", want: []string{"web-1", "web-2"}},
+		{name: "other namespace", namespace: "payments", want: []string{"api-1"}},
+		{name: "empty namespace", namespace: "orders", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClientFromInterface(fake.NewClientset(objects...), time.Second)
+			got, err := c.GetPods(t.Context(), tt.namespace)
+			if err != nil {
+				t.Fatalf("GetPods() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("GetPods() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetContainers(t *testing.T) {
+	objects := []runtime.Object{
+		pod("default", "web-1", "web"),
+		pod("default", "api-1", "api", "envoy", "log-shipper"),
+	}
+	tests := []struct {
+		name    string
+		pod     string
+		want    []string
+		wantErr bool
+	}{
+		{name: "single container", pod: "web-1", want: []string{"web"}},
+		{name: "several containers in spec order", pod: "api-1", want: []string{"api", "envoy", "log-shipper"}},
+		{name: "missing pod", pod: "web-2", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClientFromInterface(fake.NewClientset(objects...), time.Second)
+			got, err := c.GetContainers(t.Context(), "default", tt.pod)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetContainers() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("GetContainers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type ConfigMap struct {
+	Name      string
+	Namespace string
+	Keys      int
+	Created   time.Time
+}
+
+type DataKey struct {
+	Name   string
+	Size   int
+	Binary bool
+}
+
+// GetConfigMaps retrieves the list of config maps in the specified namespace.
+// It returns a slice of config maps and an error if the config maps cannot be retrieved.
+func (c *Client) GetConfigMaps(ctx context.Context, namespace string) ([]ConfigMap, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	configMaps, err := c.client.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get config maps: %w", err)
+	}
+	out := make([]ConfigMap, 0, len(configMaps.Items))
+	for _, cm := range configMaps.Items {
+		out = append(out, ConfigMap{
+			Name:      cm.Name,
+			Namespace: cm.Namespace,
+			Keys:      len(cm.Data) + len(cm.BinaryData),
+			Created:   cm.CreationTimestamp.Time,
+		})
+	}
+
+	return out, nil
+}
+
+// GetConfigMapKeys retrieves the keys of a config map together with the size of their values.
+// It returns the keys sorted by name and an error if the config map cannot be retrieved.
+func (c *Client) GetConfigMapKeys(ctx context.Context, namespace, configMapName string) ([]DataKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	cm, err := c.client.CoreV1().ConfigMaps(namespace).Get(ctx, configMapName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get config map %s in namespace %s: %w", configMapName, namespace, err)
+	}
+	out := make([]DataKey, 0, len(cm.Data)+len(cm.BinaryData))
+	for key, value := range cm.Data {
+		out = append(out, DataKey{Name: key, Size: len(value)})
+	}
+	for key, value := range cm.BinaryData {
+		out = append(out, DataKey{Name: key, Size: len(value), Binary: true})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+
+	return out, nil
+}
+
+// GetConfigMapValue retrieves the value of a single key of a config map, and the resourceVersion of the config map
+// to pass to SetConfigMapValue.
+// It returns an error if the config map cannot be retrieved, if the key does not exist or if the key holds binary data.
+func (c *Client) GetConfigMapValue(ctx context.Context, namespace, configMapName, key string) (string, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	cm, err := c.client.CoreV1().ConfigMaps(namespace).Get(ctx, configMapName, metav1.GetOptions{})
+	if err != nil {
+		return "", "", fmt.Errorf("unable to get config map %s in namespace %s: %w", configMapName, namespace, err)
+	}
+	if _, exists := cm.BinaryData[key]; exists {
+		return "", "", fmt.Errorf("key '%s' of config map %s holds binary data", key, configMapName)
+	}
+	value, exists := cm.Data[key]
+	if !exists {
+		return "", "", fmt.Errorf("key '%s' does not exist in config map %s", key, configMapName)
+	}
+
+	return value, cm.ResourceVersion, nil
+}
+
+// SetConfigMapValue updates the value of a single key of a config map, if the config map still has the
+// resourceVersion it was read with.
+// It returns an error if the config map cannot be patched, e.g. because it was changed in the meantime.
+func (c *Client) SetConfigMapValue(ctx context.Context, namespace, configMapName, key, value, resourceVersion string) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]string{"resourceVersion": resourceVersion},
+		"data":     map[string]string{key: value},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create patch: %w", err)
+	}
+	_, err = c.client.CoreV1().ConfigMaps(namespace).Patch(ctx, configMapName, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to update config map %s in namespace %s: %w", configMapName, namespace, err)
+	}
+
+	return nil
+}
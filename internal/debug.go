@@ -0,0 +1,150 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/kubectl/pkg/scheme"
+)
+
+// imagePullErrors are the waiting reasons of a container that will not start without intervention.
+var imagePullErrors = []string{"ErrImagePull", "ImagePullBackOff", "InvalidImageName", "ErrImageNeverPull"}
+
+// AddDebugContainer adds an ephemeral container running image to a pod, like kubectl debug does.
+// If targetContainer is set, the debug container shares the process namespace of that container.
+// It returns the name of the debug container and an error if the pod cannot be retrieved or updated.
+func (c *Client) AddDebugContainer(ctx context.Context, namespace, podName, image, targetContainer string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	pod, err := c.client.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("unable to get pod %s in namespace %s: %w", podName, namespace, err)
+	}
+	container := corev1.EphemeralContainer{
+		EphemeralContainerCommon: debugContainer(image),
+		TargetContainerName:      targetContainer,
+	}
+	pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, container)
+	_, err = c.client.CoreV1().Pods(namespace).UpdateEphemeralContainers(ctx, podName, pod, metav1.UpdateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("unable to add debug container to pod %s in namespace %s: %w", podName, namespace, err)
+	}
+
+	return container.Name, nil
+}
+
+// CopyPodForDebug creates a copy of a pod with an extra debug container running image, like kubectl debug --copy-to does.
+// The copy does not keep the labels of the pod, so it does not receive traffic from services, and has no probes,
+// so it is not restarted while debugging. All containers of the copy share a process namespace.
+// It returns the name of the debug container and an error if the pod cannot be retrieved or the copy cannot be created.
+func (c *Client) CopyPodForDebug(ctx context.Context, namespace, podName, copyName, image string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	pod, err := c.client.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("unable to get pod %s in namespace %s: %w", podName, namespace, err)
+	}
+	spec := pod.Spec.DeepCopy()
+	spec.NodeName = ""
+	spec.EphemeralContainers = nil
+	shareProcessNamespace := true
+	spec.ShareProcessNamespace = &shareProcessNamespace
+	for i := range spec.Containers {
+		spec.Containers[i].LivenessProbe = nil
+		spec.Containers[i].ReadinessProbe = nil
+		spec.Containers[i].StartupProbe = nil
+	}
+	container := debugContainer(image)
+	spec.Containers = append(spec.Containers, corev1.Container{
+		Name:                     container.Name,
+		Image:                    container.Image,
+		ImagePullPolicy:          container.ImagePullPolicy,
+		Stdin:                    container.Stdin,
+		TTY:                      container.TTY,
+		TerminationMessagePolicy: container.TerminationMessagePolicy,
+	})
+	podCopy := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        copyName,
+			Namespace:   namespace,
+			Annotations: pod.Annotations,
+		},
+		Spec: *spec,
+	}
+	_, err = c.client.CoreV1().Pods(namespace).Create(ctx, podCopy, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("unable to create pod %s in namespace %s: %w", copyName, namespace, err)
+	}
+
+	return container.Name, nil
+}
+
+// WaitForContainerRunning waits until a container, init container or ephemeral container of a pod is running.
+// It returns an error if the container terminates, its image cannot be pulled or it is not running within timeout.
+func (c *Client) WaitForContainerRunning(ctx context.Context, namespace, podName, containerName string, timeout time.Duration) error {
+	err := wait.PollUntilContextTimeout(ctx, time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		pod, err := c.client.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+		if err != nil {
+			return false, fmt.Errorf("unable to get pod %s in namespace %s: %w", podName, namespace, err)
+		}
+		statuses := slices.Concat(pod.Status.ContainerStatuses, pod.Status.InitContainerStatuses, pod.Status.EphemeralContainerStatuses)
+		for _, status := range statuses {
+			if status.Name != containerName {
+				continue
+			}
+			switch {
+			case status.State.Running != nil:
+				return true, nil
+			case status.State.Terminated != nil:
+				return false, fmt.Errorf("container %s of pod %s terminated: %s", containerName, podName, containerState(status.State))
+			case status.State.Waiting != nil && slices.Contains(imagePullErrors, status.State.Waiting.Reason):
+				return false, fmt.Errorf("container %s of pod %s cannot start: %s", containerName, podName, valueOr(status.State.Waiting.Message, status.State.Waiting.Reason))
+			}
+		}
+		return false, nil
+	})
+	if wait.Interrupted(err) {
+		return fmt.Errorf("container %s of pod %s is not running after %s", containerName, podName, timeout)
+	}
+
+	return err
+}
+
+// AttachToContainer attaches the terminal to the main process of the specified container, pod, and namespace.
+// It returns an error if the container cannot be attached to or if there is an issue with the terminal setup.
+func (c *Client) AttachToContainer(ctx context.Context, namespace, podName, containerName string) error {
+	req := c.client.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(podName).
+		Namespace(namespace).
+		SubResource("attach").
+		VersionedParams(&corev1.PodAttachOptions{
+			Container: containerName,
+			Stdin:     true,
+			Stdout:    true,
+			Stderr:    true,
+			TTY:       true,
+		}, scheme.ParameterCodec)
+
+	return c.streamTerminal(ctx, req.URL())
+}
+
+// debugContainer returns the definition of an interactive debug container running image with a unique name.
+func debugContainer(image string) corev1.EphemeralContainerCommon {
+	return corev1.EphemeralContainerCommon{
+		Name:                     "debugger-" + rand.String(5),
+		Image:                    image,
+		ImagePullPolicy:          corev1.PullIfNotPresent,
+		Stdin:                    true,
+		TTY:                      true,
+		TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+	}
+}
@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+type Event struct {
+	Namespace string    `json:"namespace"`
+	Object    string    `json:"object"`
+	Type      string    `json:"type"`
+	Reason    string    `json:"reason"`
+	Message   string    `json:"message"`
+	Count     int32     `json:"count"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// GetEvents retrieves the events in the specified namespace, sorted by the time they were last seen.
+// If warningsOnly is set, only Warning events are returned.
+// It returns a slice of events and an error if the events cannot be retrieved.
+func (c *Client) GetEvents(ctx context.Context, namespace string, warningsOnly bool) ([]Event, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var options metav1.ListOptions
+	if warningsOnly {
+		options.FieldSelector = fields.OneTermEqualSelector("type", corev1.EventTypeWarning).String()
+	}
+	events, err := c.client.CoreV1().Events(namespace).List(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get events: %w", err)
+	}
+
+	return newEvents(events.Items), nil
+}
+
+// newEvents converts Kubernetes events into Events, sorted by the time they were last seen.
+func newEvents(events []corev1.Event) []Event {
+	out := make([]Event, 0, len(events))
+	for _, e := range events {
+		lastSeen := e.LastTimestamp.Time
+		if lastSeen.IsZero() {
+			lastSeen = e.EventTime.Time
+		}
+		if lastSeen.IsZero() {
+			lastSeen = e.CreationTimestamp.Time
+		}
+		out = append(out, Event{
+			Namespace: e.Namespace,
+			Object:    strings.ToLower(e.InvolvedObject.Kind) + "/" + e.InvolvedObject.Name,
+			Type:      e.Type,
+			Reason:    e.Reason,
+			Message:   strings.TrimSpace(e.Message),
+			Count:     max(e.Count, 1),
+			LastSeen:  lastSeen,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].LastSeen.Before(out[j].LastSeen)
+	})
+
+	return out
+}
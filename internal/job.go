@@ -0,0 +1,219 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+type Job struct {
+	Name        string
+	Namespace   string
+	Status      string
+	Succeeded   int32
+	Completions int32
+	Duration    time.Duration
+	Created     time.Time
+}
+
+type CronJob struct {
+	Name         string
+	Namespace    string
+	Schedule     string
+	Suspended    bool
+	Active       int
+	LastSchedule time.Time
+	Created      time.Time
+}
+
+// GetJobs retrieves the list of jobs in the specified namespace.
+// It returns a slice of jobs and an error if the jobs cannot be retrieved.
+func (c *Client) GetJobs(ctx context.Context, namespace string) ([]Job, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	jobs, err := c.client.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get jobs: %w", err)
+	}
+	out := make([]Job, 0, len(jobs.Items))
+	for _, job := range jobs.Items {
+		out = append(out, newJob(&job))
+	}
+
+	return out, nil
+}
+
+// GetJobPod retrieves the most recently created pod of a job and the names of its containers.
+// It returns an error if the job or its pods cannot be retrieved or if the job has no pods.
+func (c *Client) GetJobPod(ctx context.Context, namespace, jobName string) (string, []string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	job, err := c.client.BatchV1().Jobs(namespace).Get(ctx, jobName, metav1.GetOptions{})
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to get job %s in namespace %s: %w", jobName, namespace, err)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(job.Spec.Selector)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid selector of job %s: %w", jobName, err)
+	}
+	pods, err := c.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to get pods of job %s: %w", jobName, err)
+	}
+	if len(pods.Items) == 0 {
+		return "", nil, fmt.Errorf("job %s has no pods", jobName)
+	}
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[j].CreationTimestamp.Before(&pods.Items[i].CreationTimestamp)
+	})
+	pod := pods.Items[0]
+	containers := make([]string, 0, len(pod.Spec.Containers))
+	for _, container := range pod.Spec.Containers {
+		containers = append(containers, container.Name)
+	}
+
+	return pod.Name, containers, nil
+}
+
+// GetCronJobs retrieves the list of cron jobs in the specified namespace.
+// It returns a slice of cron jobs and an error if the cron jobs cannot be retrieved.
+func (c *Client) GetCronJobs(ctx context.Context, namespace string) ([]CronJob, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	cronJobs, err := c.client.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get cron jobs: %w", err)
+	}
+	out := make([]CronJob, 0, len(cronJobs.Items))
+	for _, cj := range cronJobs.Items {
+		cronJob := CronJob{
+			Name:      cj.Name,
+			Namespace: cj.Namespace,
+			Schedule:  cj.Spec.Schedule,
+			Suspended: cj.Spec.Suspend != nil && *cj.Spec.Suspend,
+			Active:    len(cj.Status.Active),
+			Created:   cj.CreationTimestamp.Time,
+		}
+		if cj.Status.LastScheduleTime != nil {
+			cronJob.LastSchedule = cj.Status.LastScheduleTime.Time
+		}
+		out = append(out, cronJob)
+	}
+
+	return out, nil
+}
+
+// TriggerCronJob creates a job from the job template of a cron job, like kubectl create job --from=cronjob/<name>.
+// It returns the name of the created job and an error if the cron job cannot be retrieved or the job cannot be created.
+func (c *Client) TriggerCronJob(ctx context.Context, namespace, cronJobName string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	cj, err := c.client.BatchV1().CronJobs(namespace).Get(ctx, cronJobName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("unable to get cron job %s in namespace %s: %w", cronJobName, namespace, err)
+	}
+	// Job names are limited to 63 characters because they are used as a label value on the pods.
+	suffix := "-manual-" + rand.String(5)
+	name := cronJobName
+	if len(name)+len(suffix) > 63 {
+		// A name cut after a dot or dash would end in ".-manual-..." or "--manual-...", which is not a valid name.
+		name = strings.TrimRight(name[:63-len(suffix)], ".-")
+	}
+	annotations := map[string]string{"cronjob.kubernetes.io/instantiate": "manual"}
+	for key, value := range cj.Spec.JobTemplate.Annotations {
+		annotations[key] = value
+	}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name + suffix,
+			Namespace:       namespace,
+			Labels:          cj.Spec.JobTemplate.Labels,
+			Annotations:     annotations,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(cj, batchv1.SchemeGroupVersion.WithKind("CronJob"))},
+		},
+		Spec: cj.Spec.JobTemplate.Spec,
+	}
+	created, err := c.client.BatchV1().Jobs(namespace).Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("unable to create job from cron job %s: %w", cronJobName, err)
+	}
+
+	return created.Name, nil
+}
+
+// SetCronJobSuspended suspends or resumes the scheduling of a cron job.
+// It returns an error if the cron job cannot be patched.
+func (c *Client) SetCronJobSuspended(ctx context.Context, namespace, cronJobName string, suspended bool) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	patch := fmt.Sprintf(`{"spec":{"suspend":%t}}`, suspended)
+	_, err := c.client.BatchV1().CronJobs(namespace).Patch(ctx, cronJobName, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to update cron job %s in namespace %s: %w", cronJobName, namespace, err)
+	}
+
+	return nil
+}
+
+// newJob converts a Kubernetes job into a Job.
+func newJob(job *batchv1.Job) Job {
+	j := Job{
+		Name:        job.Name,
+		Namespace:   job.Namespace,
+		Status:      jobStatus(job),
+		Succeeded:   job.Status.Succeeded,
+		Completions: 1,
+		Created:     job.CreationTimestamp.Time,
+	}
+	if job.Spec.Completions != nil {
+		j.Completions = *job.Spec.Completions
+	}
+	if job.Status.StartTime != nil {
+		end := time.Now()
+		if job.Status.CompletionTime != nil {
+			end = job.Status.CompletionTime.Time
+		} else {
+			// A failed job has no completion time, it ended when it was marked as failed.
+			for _, condition := range job.Status.Conditions {
+				if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+					end = condition.LastTransitionTime.Time
+				}
+			}
+		}
+		j.Duration = end.Sub(job.Status.StartTime.Time)
+	}
+
+	return j
+}
+
+// jobStatus returns the status of a job derived from its conditions.
+func jobStatus(job *batchv1.Job) string {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return "Complete"
+		case batchv1.JobFailed:
+			return "Failed"
+		case batchv1.JobSuspended:
+			return "Suspended"
+		}
+	}
+
+	return "Running"
+}
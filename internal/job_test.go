@@ -0,0 +1,50 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestTriggerCronJob(t *testing.T) {
+	// Cutting this name to make room for the suffix leaves it ending in "-".
+	cronJobName := strings.Repeat("a", 49) + "-report"
+	clientset := fake.NewClientset(&batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Namespace: "payments", Name: cronJobName}})
+
+	name, err := NewClientFromInterface(clientset, time.Second).TriggerCronJob(t.Context(), "payments", cronJobName)
+	if err != nil {
+		t.Fatalf("TriggerCronJob() error = %v", err)
+	}
+	if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+		t.Errorf("TriggerCronJob() created job %s, which is not a valid name: %v", name, errs)
+	}
+	if !strings.HasPrefix(name, strings.Repeat("a", 49)+"-manual-") {
+		t.Errorf("TriggerCronJob() created job %s, want the cut cron job name without the trailing dash", name)
+	}
+}
+
+func TestNewJobDuration(t *testing.T) {
+	start := time.Now().Add(-time.Hour)
+	failed := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "payments", Name: "migrate"},
+		Status: batchv1.JobStatus{
+			StartTime: &metav1.Time{Time: start},
+			Conditions: []batchv1.JobCondition{{
+				Type:               batchv1.JobFailed,
+				Status:             corev1.ConditionTrue,
+				LastTransitionTime: metav1.Time{Time: start.Add(5 * time.Minute)},
+			}},
+		},
+	}
+
+	// The duration of a failed job ends when it failed instead of growing forever.
+	if got := newJob(failed); got.Status != "Failed" || got.Duration != 5*time.Minute {
+		t.Errorf("newJob() = %s after %s, want Failed after 5m", got.Status, got.Duration)
+	}
+}
@@ -19,7 +19,9 @@ type KubeConfigManager interface {
 	SetContext(contextName string) error
 	GetContextNames() []string
 	GetCurrentNamespace() (string, error)
+	GetContextNamespace(contextName string) (string, error)
 	SetNamespace(namespace string) error
+	SetContextNamespace(contextName, namespace string) error
 }
 
 // NewKubeConfig creates a new KubeConfig instance by loading the kubeconfig file from the specified path.
@@ -110,15 +112,37 @@ func (k *KubeConfig) GetCurrentNamespace() (string, error) {
 	return context.Namespace, nil
 }
 
+// GetContextNamespace returns the namespace configured for the specified context in the kubeconfig file.
+// If the context does not have a namespace set, it returns "default".
+// It returns an error if the context does not exist.
+func (k *KubeConfig) GetContextNamespace(contextName string) (string, error) {
+	context, exists := k.config.Contexts[contextName]
+	if !exists {
+		return "", fmt.Errorf("context '%s' does not exist", contextName)
+	}
+	if context.Namespace == "" {
+		return "default", nil
+	}
+
+	return context.Namespace, nil
+}
+
 // SetNamespace sets the namespace for the current context in the kubeconfig file.
 // It returns an error if there is no current context or if the current context does not exist.
 func (k *KubeConfig) SetNamespace(namespace string) error {
 	if k.config.CurrentContext == "" {
 		return fmt.Errorf("no current context set in kubeconfig")
 	}
-	context, exists := k.config.Contexts[k.config.CurrentContext]
+
+	return k.SetContextNamespace(k.config.CurrentContext, namespace)
+}
+
+// SetContextNamespace sets the namespace for the specified context in the kubeconfig file.
+// It returns an error if the context does not exist or if the kubeconfig file cannot be written.
+func (k *KubeConfig) SetContextNamespace(contextName, namespace string) error {
+	context, exists := k.config.Contexts[contextName]
 	if !exists {
-		return fmt.Errorf("current context '%s' does not exist", k.config.CurrentContext)
+		return fmt.Errorf("context '%s' does not exist", contextName)
 	}
 	if context.Namespace == namespace {
 		return nil
@@ -0,0 +1,199 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const metricsAPIPath = "/apis/metrics.k8s.io/v1beta1"
+
+// ErrMetricsUnavailable is returned when the metrics.k8s.io API is not served by the cluster.
+var ErrMetricsUnavailable = errors.New("metrics API not available (is metrics-server installed?)")
+
+// Usage holds the resource usage of a pod, container or node together with its requests and limits.
+// CPU values are in millicores and memory values in bytes. A request or limit of 0 means it is not set.
+type Usage struct {
+	CPU           int64
+	Memory        int64
+	CPURequest    int64
+	CPULimit      int64
+	MemoryRequest int64
+	MemoryLimit   int64
+}
+
+type ContainerUsage struct {
+	Name string
+	Usage
+}
+
+type PodUsage struct {
+	Name       string
+	Namespace  string
+	Containers []ContainerUsage
+	Usage
+}
+
+type NodeUsage struct {
+	Name              string
+	AllocatableCPU    int64
+	AllocatableMemory int64
+	Usage
+}
+
+type metricsList[T any] struct {
+	Items []T `json:"items"`
+}
+
+type podMetrics struct {
+	Metadata   metav1.ObjectMeta `json:"metadata"`
+	Containers []struct {
+		Name  string              `json:"name"`
+		Usage corev1.ResourceList `json:"usage"`
+	} `json:"containers"`
+}
+
+type nodeMetrics struct {
+	Metadata metav1.ObjectMeta   `json:"metadata"`
+	Usage    corev1.ResourceList `json:"usage"`
+}
+
+// GetPodUsage retrieves the resource usage of the pods matching the label selector in the specified namespace
+// from the metrics API, combined with the requests and limits of their containers.
+// It returns ErrMetricsUnavailable if the metrics API is not available.
+func (c *Client) GetPodUsage(ctx context.Context, namespace, labelSelector string) ([]PodUsage, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var metrics metricsList[podMetrics]
+	err := c.getMetrics(ctx, fmt.Sprintf("%s/namespaces/%s/pods", metricsAPIPath, namespace), labelSelector, &metrics)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := c.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get pods: %w", err)
+	}
+	specs := make(map[string]*corev1.Pod, len(pods.Items))
+	for i := range pods.Items {
+		specs[pods.Items[i].Name] = &pods.Items[i]
+	}
+
+	out := make([]PodUsage, 0, len(metrics.Items))
+	for _, m := range metrics.Items {
+		pod := PodUsage{
+			Name:      m.Metadata.Name,
+			Namespace: m.Metadata.Namespace,
+		}
+		resources := make(map[string]corev1.ResourceRequirements)
+		if spec, exists := specs[pod.Name]; exists {
+			for _, container := range spec.Spec.Containers {
+				resources[container.Name] = container.Resources
+			}
+		}
+		for _, container := range m.Containers {
+			cu := ContainerUsage{Name: container.Name}
+			cu.CPU = container.Usage.Cpu().MilliValue()
+			cu.Memory = container.Usage.Memory().Value()
+			if r, exists := resources[container.Name]; exists {
+				cu.CPURequest = r.Requests.Cpu().MilliValue()
+				cu.CPULimit = r.Limits.Cpu().MilliValue()
+				cu.MemoryRequest = r.Requests.Memory().Value()
+				cu.MemoryLimit = r.Limits.Memory().Value()
+			}
+			pod.Containers = append(pod.Containers, cu)
+			pod.CPU += cu.CPU
+			pod.Memory += cu.Memory
+			pod.CPURequest += cu.CPURequest
+			pod.CPULimit += cu.CPULimit
+			pod.MemoryRequest += cu.MemoryRequest
+			pod.MemoryLimit += cu.MemoryLimit
+		}
+		out = append(out, pod)
+	}
+
+	return out, nil
+}
+
+// GetNodeUsage retrieves the resource usage of the nodes matching the label selector from the metrics API,
+// combined with their allocatable resources and the requests and limits of the pods running on them.
+// It returns ErrMetricsUnavailable if the metrics API is not available.
+func (c *Client) GetNodeUsage(ctx context.Context, labelSelector string) ([]NodeUsage, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var metrics metricsList[nodeMetrics]
+	err := c.getMetrics(ctx, metricsAPIPath+"/nodes", labelSelector, &metrics)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := c.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get nodes: %w", err)
+	}
+	pods, err := c.client.CoreV1().Pods("").List(ctx, metav1.ListOptions{FieldSelector: activePodsSelector().String()})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get pods: %w", err)
+	}
+	requested := make(map[string]*Usage)
+	for _, pod := range pods.Items {
+		u, exists := requested[pod.Spec.NodeName]
+		if !exists {
+			u = &Usage{}
+			requested[pod.Spec.NodeName] = u
+		}
+		for _, container := range pod.Spec.Containers {
+			u.CPURequest += container.Resources.Requests.Cpu().MilliValue()
+			u.CPULimit += container.Resources.Limits.Cpu().MilliValue()
+			u.MemoryRequest += container.Resources.Requests.Memory().Value()
+			u.MemoryLimit += container.Resources.Limits.Memory().Value()
+		}
+	}
+	allocatable := make(map[string]corev1.ResourceList, len(nodes.Items))
+	for _, node := range nodes.Items {
+		allocatable[node.Name] = node.Status.Allocatable
+	}
+
+	out := make([]NodeUsage, 0, len(metrics.Items))
+	for _, m := range metrics.Items {
+		node := NodeUsage{Name: m.Metadata.Name}
+		if u, exists := requested[node.Name]; exists {
+			node.Usage = *u
+		}
+		node.CPU = m.Usage.Cpu().MilliValue()
+		node.Memory = m.Usage.Memory().Value()
+		if a, exists := allocatable[node.Name]; exists {
+			node.AllocatableCPU = a.Cpu().MilliValue()
+			node.AllocatableMemory = a.Memory().Value()
+		}
+		out = append(out, node)
+	}
+
+	return out, nil
+}
+
+// getMetrics retrieves a list from the metrics API and decodes it into out.
+// It returns ErrMetricsUnavailable if the metrics API is not registered or its backend is not reachable.
+func (c *Client) getMetrics(ctx context.Context, path, labelSelector string, out any) error {
+	req := c.client.CoreV1().RESTClient().Get().AbsPath(path)
+	if labelSelector != "" {
+		req = req.Param("labelSelector", labelSelector)
+	}
+	body, err := req.DoRaw(ctx)
+	if err != nil {
+		if apierrors.IsNotFound(err) || apierrors.IsServiceUnavailable(err) {
+			return ErrMetricsUnavailable
+		}
+		return fmt.Errorf("unable to get metrics: %w", err)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("unable to decode metrics: %w", err)
+	}
+
+	return nil
+}
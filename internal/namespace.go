@@ -0,0 +1,345 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// namespaceDeletionPollInterval is the time between checks whether a deleted namespace is gone.
+const namespaceDeletionPollInterval = 2 * time.Second
+
+// ResourceCount is the number of resources of a type in a namespace.
+type ResourceCount struct {
+	Resource string
+	Count    int
+	// Err is set if the resources could not be counted, e.g. because the user is not allowed to list them.
+	Err error
+}
+
+// NamespaceSummary describes a namespace and what it contains.
+type NamespaceSummary struct {
+	Name        string
+	Status      string
+	Labels      map[string]string
+	Annotations map[string]string
+	Created     time.Time
+	Resources   []ResourceCount
+	// Finalizers are the finalizers of the namespace itself, Conditions the messages of the conditions that explain
+	// why a terminating namespace is not gone yet.
+	Finalizers []string
+	Conditions []string
+}
+
+// NamespaceDetails describes a namespace, what it contains and the limits that apply to it.
+type NamespaceDetails struct {
+	NamespaceSummary
+	// PodPhases counts the pods in the namespace by phase, e.g. Running. It is empty if the pods could not be listed.
+	PodPhases   map[string]int
+	Quotas      []Quota
+	LimitRanges []LimitRange
+	// QuotasErr and LimitRangesErr are set if the quotas or limit ranges could not be retrieved, e.g. because the user
+	// is not allowed to list them.
+	QuotasErr      error
+	LimitRangesErr error
+}
+
+// Quota is a ResourceQuota with the usage of each resource it limits.
+type Quota struct {
+	Name      string
+	Resources []QuotaResource
+}
+
+// QuotaResource is the usage and the hard limit of a resource in a ResourceQuota.
+type QuotaResource struct {
+	Resource string
+	Used     string
+	Hard     string
+}
+
+// LimitRange is a LimitRange with the constraints it sets per resource.
+type LimitRange struct {
+	Name   string
+	Limits []LimitRangeItem
+}
+
+// LimitRangeItem is the constraint of a LimitRange on a resource of a type of object, e.g. the cpu of a Container.
+// Values that are not set are empty.
+type LimitRangeItem struct {
+	Type           string
+	Resource       string
+	Min            string
+	Max            string
+	DefaultRequest string
+	Default        string
+}
+
+// summaryListOptions lists a single item; the API server reports how many items remain.
+var summaryListOptions = metav1.ListOptions{Limit: 1}
+
+// namespaceResources are the resources counted in a namespace summary.
+var namespaceResources = []struct {
+	name  string
+	count func(ctx context.Context, c *Client, namespace string) (int, error)
+}{
+	{"pods", func(ctx context.Context, c *Client, namespace string) (int, error) {
+		return countItems(c.client.CoreV1().Pods(namespace).List(ctx, summaryListOptions))
+	}},
+	{"deployments", func(ctx context.Context, c *Client, namespace string) (int, error) {
+		return countItems(c.client.AppsV1().Deployments(namespace).List(ctx, summaryListOptions))
+	}},
+	{"statefulsets", func(ctx context.Context, c *Client, namespace string) (int, error) {
+		return countItems(c.client.AppsV1().StatefulSets(namespace).List(ctx, summaryListOptions))
+	}},
+	{"daemonsets", func(ctx context.Context, c *Client, namespace string) (int, error) {
+		return countItems(c.client.AppsV1().DaemonSets(namespace).List(ctx, summaryListOptions))
+	}},
+	{"jobs", func(ctx context.Context, c *Client, namespace string) (int, error) {
+		return countItems(c.client.BatchV1().Jobs(namespace).List(ctx, summaryListOptions))
+	}},
+	{"cronjobs", func(ctx context.Context, c *Client, namespace string) (int, error) {
+		return countItems(c.client.BatchV1().CronJobs(namespace).List(ctx, summaryListOptions))
+	}},
+	{"services", func(ctx context.Context, c *Client, namespace string) (int, error) {
+		return countItems(c.client.CoreV1().Services(namespace).List(ctx, summaryListOptions))
+	}},
+	{"ingresses", func(ctx context.Context, c *Client, namespace string) (int, error) {
+		return countItems(c.client.NetworkingV1().Ingresses(namespace).List(ctx, summaryListOptions))
+	}},
+	{"configmaps", func(ctx context.Context, c *Client, namespace string) (int, error) {
+		return countItems(c.client.CoreV1().ConfigMaps(namespace).List(ctx, summaryListOptions))
+	}},
+	{"secrets", func(ctx context.Context, c *Client, namespace string) (int, error) {
+		return countItems(c.client.CoreV1().Secrets(namespace).List(ctx, summaryListOptions))
+	}},
+	{"persistentvolumeclaims", func(ctx context.Context, c *Client, namespace string) (int, error) {
+		return countItems(c.client.CoreV1().PersistentVolumeClaims(namespace).List(ctx, summaryListOptions))
+	}},
+}
+
+// GetNamespaceSummary retrieves a namespace and counts the common resources in it. Resources that cannot be counted
+// have an error in the summary instead of failing the whole summary.
+// It returns an error if the namespace cannot be retrieved.
+func (c *Client) GetNamespaceSummary(ctx context.Context, namespace string) (*NamespaceSummary, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	ns, err := c.client.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get namespace %s: %w", namespace, err)
+	}
+	summary := namespaceSummary(ns)
+	summary.Resources = make([]ResourceCount, len(namespaceResources))
+	var wg sync.WaitGroup
+	for i, resource := range namespaceResources {
+		wg.Go(func() {
+			count, err := resource.count(ctx, c, namespace)
+			summary.Resources[i] = ResourceCount{Resource: resource.name, Count: count, Err: err}
+		})
+	}
+	wg.Wait()
+
+	return summary, nil
+}
+
+// GetNamespaceDetails retrieves a namespace, counts the resources in it and the pods by phase, and retrieves its
+// ResourceQuotas and LimitRanges. Resources, quotas and limit ranges that cannot be retrieved have an error in the
+// details instead of failing the whole description.
+// It returns an error if the namespace cannot be retrieved.
+func (c *Client) GetNamespaceDetails(ctx context.Context, namespace string) (*NamespaceDetails, error) {
+	summary, err := c.GetNamespaceSummary(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	details := &NamespaceDetails{NamespaceSummary: *summary, PodPhases: map[string]int{}}
+	// The pods that cannot be listed already have an error in the summary, they are just not counted by phase.
+	if pods, err := c.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		for _, pod := range pods.Items {
+			details.PodPhases[string(pod.Status.Phase)]++
+		}
+	}
+
+	quotas, err := c.client.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		details.QuotasErr = fmt.Errorf("unable to get resource quotas in namespace %s: %w", namespace, err)
+		quotas = &corev1.ResourceQuotaList{}
+	}
+	for _, quota := range quotas.Items {
+		q := Quota{Name: quota.Name}
+		for _, name := range slices.Sorted(maps.Keys(quota.Status.Hard)) {
+			used := quota.Status.Used[name]
+			hard := quota.Status.Hard[name]
+			q.Resources = append(q.Resources, QuotaResource{Resource: string(name), Used: used.String(), Hard: hard.String()})
+		}
+		details.Quotas = append(details.Quotas, q)
+	}
+
+	limitRanges, err := c.client.CoreV1().LimitRanges(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		details.LimitRangesErr = fmt.Errorf("unable to get limit ranges in namespace %s: %w", namespace, err)
+		limitRanges = &corev1.LimitRangeList{}
+	}
+	for _, limitRange := range limitRanges.Items {
+		lr := LimitRange{Name: limitRange.Name}
+		for _, limit := range limitRange.Spec.Limits {
+			names := slices.Concat(slices.Collect(maps.Keys(limit.Min)), slices.Collect(maps.Keys(limit.Max)),
+				slices.Collect(maps.Keys(limit.DefaultRequest)), slices.Collect(maps.Keys(limit.Default)))
+			slices.Sort(names)
+			for _, name := range slices.Compact(names) {
+				lr.Limits = append(lr.Limits, LimitRangeItem{
+					Type:           string(limit.Type),
+					Resource:       string(name),
+					Min:            quantity(limit.Min, name),
+					Max:            quantity(limit.Max, name),
+					DefaultRequest: quantity(limit.DefaultRequest, name),
+					Default:        quantity(limit.Default, name),
+				})
+			}
+		}
+		details.LimitRanges = append(details.LimitRanges, lr)
+	}
+
+	return details, nil
+}
+
+// CreateNamespace creates a namespace with the specified labels and annotations.
+// It returns an error if the namespace cannot be created, e.g. because it already exists.
+func (c *Client) CreateNamespace(ctx context.Context, namespace string, labels, annotations map[string]string) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        namespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+	}
+	_, err := c.client.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to create namespace %s: %w", namespace, err)
+	}
+	c.forgetNamespaces()
+
+	return nil
+}
+
+// DeleteNamespace deletes a namespace. The namespace is terminating until all resources in it are deleted, use
+// WaitForNamespaceDeleted to wait until it is gone.
+// It returns an error if the namespace cannot be deleted.
+func (c *Client) DeleteNamespace(ctx context.Context, namespace string) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	err := c.client.CoreV1().Namespaces().Delete(ctx, namespace, metav1.DeleteOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to delete namespace %s: %w", namespace, err)
+	}
+	c.forgetNamespaces()
+
+	return nil
+}
+
+// WaitForNamespaceDeleted waits until a deleted namespace is gone. Changes of the conditions that explain why the
+// namespace is still terminating, e.g. resources with finalizers, are passed to progress.
+// It returns the summary of the namespace if it still exists after the timeout, and an error if it cannot be
+// retrieved or the context is cancelled.
+func (c *Client) WaitForNamespaceDeleted(ctx context.Context, namespace string, timeout time.Duration, progress func(string)) (*NamespaceSummary, error) {
+	var summary *NamespaceSummary
+	var reported []string
+	err := wait.PollUntilContextTimeout(ctx, namespaceDeletionPollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		requestCtx, cancel := context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+		ns, err := c.client.CoreV1().Namespaces().Get(requestCtx, namespace, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("unable to get namespace %s: %w", namespace, err)
+		}
+		summary = namespaceSummary(ns)
+		for _, condition := range summary.Conditions {
+			if !slices.Contains(reported, condition) {
+				progress(condition)
+			}
+		}
+		reported = summary.Conditions
+		return false, nil
+	})
+	if wait.Interrupted(err) && ctx.Err() == nil {
+		return summary, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// namespaceSummary describes a namespace without counting the resources in it.
+func namespaceSummary(ns *corev1.Namespace) *NamespaceSummary {
+	summary := &NamespaceSummary{
+		Name:        ns.Name,
+		Status:      string(ns.Status.Phase),
+		Labels:      ns.Labels,
+		Annotations: ns.Annotations,
+		Created:     ns.CreationTimestamp.Time,
+		Finalizers:  slices.Clone(ns.Finalizers),
+	}
+	for _, finalizer := range ns.Spec.Finalizers {
+		summary.Finalizers = append(summary.Finalizers, string(finalizer))
+	}
+	for _, condition := range ns.Status.Conditions {
+		// The namespace controller sets the conditions to True while they block the deletion.
+		if condition.Status == corev1.ConditionTrue && condition.Message != "" {
+			summary.Conditions = append(summary.Conditions, condition.Message)
+		}
+	}
+
+	return summary
+}
+
+// quantity returns the quantity of a resource in a resource list, or an empty string if the list does not set it.
+func quantity(list corev1.ResourceList, name corev1.ResourceName) string {
+	q, exists := list[name]
+	if !exists {
+		return ""
+	}
+
+	return q.String()
+}
+
+// countItems returns the number of items of a list retrieved with summaryListOptions, including the items that were
+// not returned.
+func countItems(list runtime.Object, err error) (int, error) {
+	if err != nil {
+		return 0, err
+	}
+	count := meta.LenList(list)
+	if l, ok := list.(metav1.ListInterface); ok && l.GetRemainingItemCount() != nil {
+		count += int(*l.GetRemainingItemCount())
+	}
+
+	return count, nil
+}
+
+// forgetNamespaces removes the cached namespaces of the cluster after a namespace is created or deleted.
+func (c *Client) forgetNamespaces() {
+	if c.cache != nil {
+		// A stale cache entry only makes the pickers show the old namespaces until it expires.
+		_ = c.cache.Delete(c.namespacesCacheKey())
+	}
+}
@@ -0,0 +1,150 @@
+package internal
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetNamespaceSummary(t *testing.T) {
+	clientset := fake.NewClientset(
+		namespace("payments"),
+		pod("payments", "api-1", "api"),
+		pod("payments", "api-2", "api"),
+		pod("default", "web-1", "web"),
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "payments", Name: "db"}},
+	)
+	forbidden(clientset, "list", "secrets")
+
+	summary, err := NewClientFromInterface(clientset, time.Second).GetNamespaceSummary(t.Context(), "payments")
+	if err != nil {
+		t.Fatalf("GetNamespaceSummary() error = %v", err)
+	}
+	counts := map[string]int{}
+	for _, resource := range summary.Resources {
+		if resource.Resource == "secrets" {
+			if !IsForbidden(resource.Err) {
+				t.Errorf("GetNamespaceSummary() secrets error = %v, want a forbidden error", resource.Err)
+			}
+			continue
+		}
+		if resource.Err != nil {
+			t.Errorf("GetNamespaceSummary() %s error = %v", resource.Resource, resource.Err)
+		}
+		counts[resource.Resource] = resource.Count
+	}
+	if counts["pods"] != 2 || counts["services"] != 0 {
+		t.Errorf("GetNamespaceSummary() counts = %v, want 2 pods and no services", counts)
+	}
+
+	if _, err := NewClientFromInterface(clientset, time.Second).GetNamespaceSummary(t.Context(), "orders"); !IsNotFound(err) {
+		t.Errorf("GetNamespaceSummary(orders) error = %v, want a not found error", err)
+	}
+}
+
+func TestGetNamespaceDetails(t *testing.T) {
+	running := pod("payments", "api-1", "api")
+	running.Status.Phase = corev1.PodRunning
+	failed := pod("payments", "migrate-1", "migrate")
+	failed.Status.Phase = corev1.PodFailed
+	clientset := fake.NewClientset(
+		namespace("payments"),
+		running,
+		failed,
+		&corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "payments", Name: "compute"},
+			Status: corev1.ResourceQuotaStatus{
+				Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("2"), corev1.ResourcePods: resource.MustParse("10")},
+				Used: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("500m")},
+			},
+		},
+		&corev1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "payments", Name: "defaults"},
+			Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{{
+				Type:           corev1.LimitTypeContainer,
+				Max:            corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+				Default:        corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("256Mi")},
+				DefaultRequest: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+			}}},
+		},
+	)
+
+	details, err := NewClientFromInterface(clientset, time.Second).GetNamespaceDetails(t.Context(), "payments")
+	if err != nil {
+		t.Fatalf("GetNamespaceDetails() error = %v", err)
+	}
+	if details.PodPhases["Running"] != 1 || details.PodPhases["Failed"] != 1 {
+		t.Errorf("GetNamespaceDetails() pod phases = %v, want 1 Running and 1 Failed", details.PodPhases)
+	}
+	wantQuotas := []Quota{{Name: "compute", Resources: []QuotaResource{
+		{Resource: "pods", Used: "0", Hard: "10"},
+		{Resource: "requests.cpu", Used: "500m", Hard: "2"},
+	}}}
+	if !reflect.DeepEqual(details.Quotas, wantQuotas) {
+		t.Errorf("GetNamespaceDetails() quotas = %+v, want %+v", details.Quotas, wantQuotas)
+	}
+	wantLimitRanges := []LimitRange{{Name: "defaults", Limits: []LimitRangeItem{
+		{Type: "Container", Resource: "cpu", Max: "2", DefaultRequest: "100m", Default: "500m"},
+		{Type: "Container", Resource: "memory", Default: "256Mi"},
+	}}}
+	if !reflect.DeepEqual(details.LimitRanges, wantLimitRanges) {
+		t.Errorf("GetNamespaceDetails() limit ranges = %+v, want %+v", details.LimitRanges, wantLimitRanges)
+	}
+}
+
+func TestGetNamespaceDetailsForbidden(t *testing.T) {
+	clientset := fake.NewClientset(namespace("payments"), pod("payments", "api-1", "api"))
+	forbidden(clientset, "list", "resourcequotas")
+	forbidden(clientset, "list", "limitranges")
+
+	details, err := NewClientFromInterface(clientset, time.Second).GetNamespaceDetails(t.Context(), "payments")
+	if err != nil {
+		t.Fatalf("GetNamespaceDetails() error = %v, want the details without quotas and limit ranges", err)
+	}
+	if !IsForbidden(details.QuotasErr) || !IsForbidden(details.LimitRangesErr) {
+		t.Errorf("GetNamespaceDetails() errors = %v, %v, want forbidden", details.QuotasErr, details.LimitRangesErr)
+	}
+	if len(details.PodPhases) != 1 {
+		t.Errorf("GetNamespaceDetails() pod phases = %v, want the pod counted", details.PodPhases)
+	}
+}
+
+func TestWaitForNamespaceDeleted(t *testing.T) {
+	terminating := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "payments"},
+		Spec:       corev1.NamespaceSpec{Finalizers: []corev1.FinalizerName{corev1.FinalizerKubernetes}},
+		Status: corev1.NamespaceStatus{
+			Phase: corev1.NamespaceTerminating,
+			Conditions: []corev1.NamespaceCondition{
+				{Type: corev1.NamespaceFinalizersRemaining, Status: corev1.ConditionTrue, Message: "Some content in the namespace has finalizers remaining: example.com/protect in 1 resource instances"},
+				{Type: corev1.NamespaceDeletionDiscoveryFailure, Status: corev1.ConditionFalse, Message: "All resources successfully discovered"},
+			},
+		},
+	}
+	c := NewClientFromInterface(fake.NewClientset(terminating), time.Second)
+
+	var progress []string
+	remaining, err := c.WaitForNamespaceDeleted(t.Context(), "payments", 100*time.Millisecond, func(msg string) {
+		progress = append(progress, msg)
+	})
+	if err != nil {
+		t.Fatalf("WaitForNamespaceDeleted() error = %v", err)
+	}
+	if remaining == nil || !slices.Equal(remaining.Finalizers, []string{"kubernetes"}) {
+		t.Fatalf("WaitForNamespaceDeleted() = %+v, want the terminating namespace with its finalizer", remaining)
+	}
+	if want := []string{terminating.Status.Conditions[0].Message}; !slices.Equal(progress, want) {
+		t.Errorf("WaitForNamespaceDeleted() progress = %q, want %q", progress, want)
+	}
+
+	remaining, err = c.WaitForNamespaceDeleted(t.Context(), "orders", 100*time.Millisecond, func(string) {})
+	if err != nil || remaining != nil {
+		t.Errorf("WaitForNamespaceDeleted(orders) = %+v, %v, want nil for a namespace that is gone", remaining, err)
+	}
+}
@@ -0,0 +1,438 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+// evictionRetryInterval is the time to wait before retrying an eviction that was refused,
+// for example because it would violate a PodDisruptionBudget.
+const evictionRetryInterval = 5 * time.Second
+
+type Node struct {
+	Name              string
+	Status            string
+	Roles             string
+	Version           string
+	InternalIP        string
+	OSImage           string
+	KernelVersion     string
+	ContainerRuntime  string
+	AllocatableCPU    string
+	AllocatableMemory string
+	Pods              int
+	PodCapacity       int64
+	Taints            []string
+	Conditions        []NodeCondition
+	Created           time.Time
+}
+
+// NodeShellCommand is the command executed in a node shell pod to enter the namespaces of the init process of the node.
+var NodeShellCommand = []string{"nsenter", "-t", "1", "-m", "-u", "-i", "-n", "-p", "--", "sh", "-c", "command -v bash >/dev/null 2>&1 && exec bash || exec sh"}
+
+// nodeShellDeadline is the time after which a node shell pod is stopped if it was not deleted, for example because
+// wimkube was killed.
+const nodeShellDeadline = 8 * time.Hour
+
+type NodeCondition struct {
+	Type    string
+	Status  string
+	Reason  string
+	Message string
+}
+
+type DrainOptions struct {
+	IgnoreDaemonSets   bool
+	DeleteEmptyDirData bool
+	Force              bool
+	Timeout            time.Duration
+}
+
+// GetNodes retrieves the list of nodes in the Kubernetes cluster together with the number of pods running on each node.
+// It returns a slice of nodes and an error if the nodes or pods cannot be retrieved.
+func (c *Client) GetNodes(ctx context.Context) ([]Node, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	nodes, err := c.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get nodes: %w", err)
+	}
+	pods, err := c.client.CoreV1().Pods("").List(ctx, metav1.ListOptions{FieldSelector: activePodsSelector().String()})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get pods: %w", err)
+	}
+	podCount := make(map[string]int)
+	for _, pod := range pods.Items {
+		podCount[pod.Spec.NodeName]++
+	}
+	out := make([]Node, 0, len(nodes.Items))
+	for _, node := range nodes.Items {
+		n := newNode(&node)
+		n.Pods = podCount[node.Name]
+		out = append(out, n)
+	}
+
+	return out, nil
+}
+
+// GetNode retrieves a single node and the names of the pods running on it in the form namespace/name.
+// It returns an error if the node or its pods cannot be retrieved.
+func (c *Client) GetNode(ctx context.Context, nodeName string) (*Node, []string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	node, err := c.client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get node %s: %w", nodeName, err)
+	}
+	pods, err := c.client.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: fields.AndSelectors(activePodsSelector(), fields.OneTermEqualSelector("spec.nodeName", nodeName)).String(),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get pods on node %s: %w", nodeName, err)
+	}
+	podNames := make([]string, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		podNames = append(podNames, pod.Namespace+"/"+pod.Name)
+	}
+	sort.Strings(podNames)
+	n := newNode(node)
+	n.Pods = len(podNames)
+
+	return &n, podNames, nil
+}
+
+// SetNodeSchedulable marks a node as schedulable (uncordon) or unschedulable (cordon).
+// It returns an error if the node cannot be patched.
+func (c *Client) SetNodeSchedulable(ctx context.Context, nodeName string, schedulable bool) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	patch := fmt.Sprintf(`{"spec":{"unschedulable":%t}}`, !schedulable)
+	_, err := c.client.CoreV1().Nodes().Patch(ctx, nodeName, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to update node %s: %w", nodeName, err)
+	}
+
+	return nil
+}
+
+// DrainNode cordons a node and evicts all pods running on it through the eviction API, which respects PodDisruptionBudgets.
+// Evictions that are refused are retried until the timeout of the options expires. Progress messages are passed to
+// the progress function, which may be called concurrently.
+// It returns an error if the node cannot be cordoned, if pods cannot be evicted or if the timeout expires.
+func (c *Client) DrainNode(ctx context.Context, nodeName string, opts DrainOptions, progress func(string)) error {
+	if err := c.SetNodeSchedulable(ctx, nodeName, false); err != nil {
+		return err
+	}
+	progress(fmt.Sprintf("node/%s cordoned", nodeName))
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	pods, err := c.podsToEvict(ctx, nodeName, opts, progress)
+	if err != nil {
+		return err
+	}
+	if len(pods) == 0 {
+		progress(fmt.Sprintf("node/%s drained", nodeName))
+		return nil
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		errs      []error
+		remaining = len(pods)
+	)
+	for _, pod := range pods {
+		wg.Go(func() {
+			err := c.evictPod(ctx, pod, progress)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			remaining--
+			progress(fmt.Sprintf("pod/%s evicted from namespace %s (%d remaining)", pod.Name, pod.Namespace, remaining))
+		})
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		return fmt.Errorf("unable to drain node %s: %w", nodeName, errors.Join(errs...))
+	}
+	progress(fmt.Sprintf("node/%s drained", nodeName))
+
+	return nil
+}
+
+// podsToEvict returns the pods on a node that must be evicted to drain it.
+// It returns an error if pods are found that cannot be evicted with the given options.
+func (c *Client) podsToEvict(ctx context.Context, nodeName string, opts DrainOptions, progress func(string)) ([]corev1.Pod, error) {
+	listCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	pods, err := c.client.CoreV1().Pods("").List(listCtx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get pods on node %s: %w", nodeName, err)
+	}
+	var (
+		out      []corev1.Pod
+		problems []string
+	)
+	for _, pod := range pods.Items {
+		name := pod.Namespace + "/" + pod.Name
+		if _, mirror := pod.Annotations[corev1.MirrorPodAnnotationKey]; mirror {
+			continue
+		}
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			out = append(out, pod)
+			continue
+		}
+		controller := metav1.GetControllerOf(&pod)
+		if controller != nil && controller.Kind == "DaemonSet" {
+			if !opts.IgnoreDaemonSets {
+				problems = append(problems, fmt.Sprintf("pod %s is managed by a DaemonSet (use --ignore-daemonsets)", name))
+			} else {
+				progress(fmt.Sprintf("ignoring DaemonSet-managed pod %s", name))
+			}
+			continue
+		}
+		if controller == nil && !opts.Force {
+			problems = append(problems, fmt.Sprintf("pod %s is not managed by a controller (use --force)", name))
+			continue
+		}
+		if hasEmptyDir(&pod) && !opts.DeleteEmptyDirData {
+			problems = append(problems, fmt.Sprintf("pod %s uses emptyDir storage (use --delete-emptydir-data)", name))
+			continue
+		}
+		out = append(out, pod)
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("cannot drain node %s:\n  %s", nodeName, strings.Join(problems, "\n  "))
+	}
+
+	return out, nil
+}
+
+// evictPod evicts a pod and waits until it is deleted.
+// Evictions refused with 429 Too Many Requests (PodDisruptionBudget) are retried until the context expires.
+func (c *Client) evictPod(ctx context.Context, pod corev1.Pod, progress func(string)) error {
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		},
+	}
+	for {
+		err := c.client.PolicyV1().Evictions(pod.Namespace).Evict(ctx, eviction)
+		if err == nil || apierrors.IsNotFound(err) {
+			break
+		}
+		if !apierrors.IsTooManyRequests(err) {
+			return fmt.Errorf("unable to evict pod %s in namespace %s: %w", pod.Name, pod.Namespace, err)
+		}
+		progress(fmt.Sprintf("pod/%s in namespace %s cannot be evicted yet (%v), retrying in %s", pod.Name, pod.Namespace, err, evictionRetryInterval))
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out evicting pod %s in namespace %s: %w", pod.Name, pod.Namespace, ctx.Err())
+		case <-time.After(evictionRetryInterval):
+		}
+	}
+
+	for {
+		current, err := c.client.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) || (err == nil && current.UID != pod.UID) {
+			return nil
+		}
+		if err != nil && ctx.Err() == nil {
+			return fmt.Errorf("unable to get pod %s in namespace %s: %w", pod.Name, pod.Namespace, err)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for pod %s in namespace %s to be deleted: %w", pod.Name, pod.Namespace, ctx.Err())
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// CreateNodeShellPod creates a privileged pod running image on a node that shares the PID, network and IPC namespaces
+// of the node and mounts its root filesystem at /host. The pod tolerates all taints, so it also runs on cordoned nodes.
+// It returns the name of the pod and of its container and an error if the pod cannot be created.
+func (c *Client) CreateNodeShellPod(ctx context.Context, namespace, nodeName, image string) (string, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	// Pod names are limited to 63 characters to be usable as a hostname.
+	suffix := "-" + rand.String(5)
+	name := "node-shell-" + nodeName
+	if len(name)+len(suffix) > 63 {
+		name = name[:63-len(suffix)]
+	}
+	privileged := true
+	deadline := int64(nodeShellDeadline.Seconds())
+	gracePeriod := int64(0)
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      strings.TrimSuffix(name, "-") + suffix,
+			Namespace: namespace,
+			Labels:    map[string]string{"app.kubernetes.io/managed-by": "wimkube"},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:                      nodeName,
+			HostPID:                       true,
+			HostNetwork:                   true,
+			HostIPC:                       true,
+			RestartPolicy:                 corev1.RestartPolicyNever,
+			ActiveDeadlineSeconds:         &deadline,
+			TerminationGracePeriodSeconds: &gracePeriod,
+			Tolerations:                   []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+			Containers: []corev1.Container{{
+				Name:            "shell",
+				Image:           image,
+				Command:         []string{"sleep", fmt.Sprint(deadline)},
+				SecurityContext: &corev1.SecurityContext{Privileged: &privileged},
+				VolumeMounts:    []corev1.VolumeMount{{Name: "host-root", MountPath: "/host"}},
+			}},
+			Volumes: []corev1.Volume{{
+				Name:         "host-root",
+				VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/"}},
+			}},
+		},
+	}
+	created, err := c.client.CoreV1().Pods(namespace).Create(ctx, pod, metav1.CreateOptions{})
+	if err != nil {
+		return "", "", fmt.Errorf("unable to create node shell pod on node %s: %w", nodeName, err)
+	}
+
+	return created.Name, pod.Spec.Containers[0].Name, nil
+}
+
+// hasEmptyDir reports whether a pod uses an emptyDir volume, whose data is lost when the pod is evicted.
+func hasEmptyDir(pod *corev1.Pod) bool {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.EmptyDir != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// activePodsSelector selects pods that have not terminated yet.
+func activePodsSelector() fields.Selector {
+	return fields.AndSelectors(
+		fields.OneTermNotEqualSelector("status.phase", string(corev1.PodSucceeded)),
+		fields.OneTermNotEqualSelector("status.phase", string(corev1.PodFailed)),
+	)
+}
+
+// newNode converts a Kubernetes node into a Node.
+func newNode(node *corev1.Node) Node {
+	n := Node{
+		Name:              node.Name,
+		Status:            nodeStatus(node),
+		Roles:             nodeRoles(node),
+		Version:           node.Status.NodeInfo.KubeletVersion,
+		OSImage:           node.Status.NodeInfo.OSImage,
+		KernelVersion:     node.Status.NodeInfo.KernelVersion,
+		ContainerRuntime:  node.Status.NodeInfo.ContainerRuntimeVersion,
+		AllocatableCPU:    node.Status.Allocatable.Cpu().String(),
+		AllocatableMemory: formatMemory(node.Status.Allocatable.Memory()),
+		PodCapacity:       node.Status.Allocatable.Pods().Value(),
+		Created:           node.CreationTimestamp.Time,
+	}
+	for _, address := range node.Status.Addresses {
+		if address.Type == corev1.NodeInternalIP {
+			n.InternalIP = address.Address
+			break
+		}
+	}
+	for _, taint := range node.Spec.Taints {
+		n.Taints = append(n.Taints, taint.ToString())
+	}
+	for _, condition := range node.Status.Conditions {
+		n.Conditions = append(n.Conditions, NodeCondition{
+			Type:    string(condition.Type),
+			Status:  string(condition.Status),
+			Reason:  condition.Reason,
+			Message: condition.Message,
+		})
+	}
+
+	return n
+}
+
+// nodeStatus returns the status of a node in the same way kubectl reports it (e.g. Ready,SchedulingDisabled).
+func nodeStatus(node *corev1.Node) string {
+	status := "Unknown"
+	for _, condition := range node.Status.Conditions {
+		if condition.Type != corev1.NodeReady {
+			continue
+		}
+		switch condition.Status {
+		case corev1.ConditionTrue:
+			status = "Ready"
+		case corev1.ConditionFalse:
+			status = "NotReady"
+		}
+	}
+	if node.Spec.Unschedulable {
+		status += ",SchedulingDisabled"
+	}
+
+	return status
+}
+
+// nodeRoles returns the roles of a node derived from its node-role.kubernetes.io/<role> and kubernetes.io/role labels.
+func nodeRoles(node *corev1.Node) string {
+	var roles []string
+	for label, value := range node.Labels {
+		switch {
+		case strings.HasPrefix(label, "node-role.kubernetes.io/"):
+			if role := strings.TrimPrefix(label, "node-role.kubernetes.io/"); role != "" {
+				roles = append(roles, role)
+			}
+		case label == "kubernetes.io/role" && value != "":
+			roles = append(roles, value)
+		}
+	}
+	if len(roles) == 0 {
+		return "<none>"
+	}
+	sort.Strings(roles)
+
+	return strings.Join(roles, ",")
+}
+
+// formatMemory formats a memory quantity in Gi or Mi with one decimal.
+func formatMemory(q *resource.Quantity) string {
+	const mebibyte = 1024 * 1024
+	mi := float64(q.Value()) / mebibyte
+	if mi >= 1024 {
+		return fmt.Sprintf("%.1fGi", mi/1024)
+	}
+
+	return fmt.Sprintf("%.1fMi", mi)
+}
@@ -0,0 +1,303 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+)
+
+type PodSummary struct {
+	Name       string
+	Namespace  string
+	Ready      string
+	Status     string
+	Restarts   int32
+	Node       string
+	Containers []string
+	Created    time.Time
+}
+
+type PodDetails struct {
+	PodSummary
+	IP         string
+	QoSClass   string
+	StartTime  time.Time
+	Labels     []string
+	Conditions []string
+	Containers []ContainerDetails
+	Events     []Event
+}
+
+type ContainerDetails struct {
+	Name     string
+	Image    string
+	State    string
+	Ready    bool
+	Restarts int32
+}
+
+// PodEvent is a change to a pod reported by WatchPods. Type is ADDED, MODIFIED or DELETED.
+type PodEvent struct {
+	Type string
+	Pod  PodSummary
+}
+
+// GetPodSummaries retrieves the pods in the specified namespace together with their status, in the same way kubectl get pods reports them.
+// It returns a slice of pod summaries and an error if the pods cannot be retrieved.
+func (c *Client) GetPodSummaries(ctx context.Context, namespace string) ([]PodSummary, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	pods, err := c.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get pods: %w", err)
+	}
+	out := make([]PodSummary, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		out = append(out, newPodSummary(&pod))
+	}
+
+	return out, nil
+}
+
+// GetPodDetails retrieves a pod together with the status of its containers and its recent events.
+// It returns an error if the pod or its events cannot be retrieved.
+func (c *Client) GetPodDetails(ctx context.Context, namespace, podName string) (*PodDetails, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	pod, err := c.client.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get pod %s in namespace %s: %w", podName, namespace, err)
+	}
+	events, err := c.client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.AndSelectors(
+			fields.OneTermEqualSelector("involvedObject.kind", "Pod"),
+			fields.OneTermEqualSelector("involvedObject.name", podName),
+		).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get events of pod %s in namespace %s: %w", podName, namespace, err)
+	}
+
+	details := &PodDetails{
+		PodSummary: newPodSummary(pod),
+		IP:         pod.Status.PodIP,
+		QoSClass:   string(pod.Status.QOSClass),
+	}
+	if pod.Status.StartTime != nil {
+		details.StartTime = pod.Status.StartTime.Time
+	}
+	for key, value := range pod.Labels {
+		details.Labels = append(details.Labels, key+"="+value)
+	}
+	sort.Strings(details.Labels)
+	for _, condition := range pod.Status.Conditions {
+		details.Conditions = append(details.Conditions, fmt.Sprintf("%s=%s", condition.Type, condition.Status))
+	}
+	statuses := make(map[string]corev1.ContainerStatus, len(pod.Status.ContainerStatuses))
+	for _, status := range pod.Status.ContainerStatuses {
+		statuses[status.Name] = status
+	}
+	for _, container := range pod.Spec.Containers {
+		cd := ContainerDetails{
+			Name:  container.Name,
+			Image: container.Image,
+			State: "Waiting",
+		}
+		if status, exists := statuses[container.Name]; exists {
+			cd.State = containerState(status.State)
+			cd.Ready = status.Ready
+			cd.Restarts = status.RestartCount
+		}
+		details.Containers = append(details.Containers, cd)
+	}
+	details.Events = newEvents(events.Items)
+
+	return details, nil
+}
+
+// WatchPods lists the pods in the specified namespace and then watches them for changes until ctx is cancelled or
+// handle returns false. handle is called once with an ADDED event for every existing pod and then for every change.
+// It returns an error if the pods cannot be listed or watched.
+func (c *Client) WatchPods(ctx context.Context, namespace string, handle func(events []PodEvent) bool) error {
+	listCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	pods, err := c.client.CoreV1().Pods(namespace).List(listCtx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to get pods: %w", err)
+	}
+	initial := make([]PodEvent, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		initial = append(initial, PodEvent{Type: string(watch.Added), Pod: newPodSummary(&pod)})
+	}
+	if !handle(initial) {
+		return nil
+	}
+
+	// The retry watcher resumes the watch from the last seen resource version when the connection is closed.
+	watcher, err := watchtools.NewRetryWatcherWithContext(ctx, pods.ResourceVersion, &cache.ListWatch{
+		WatchFuncWithContext: func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+			return c.client.CoreV1().Pods(namespace).Watch(ctx, options)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to watch pods: %w", err)
+	}
+	defer watcher.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return nil
+			}
+			switch event.Type {
+			case watch.Added, watch.Modified, watch.Deleted:
+				pod, ok := event.Object.(*corev1.Pod)
+				if !ok {
+					continue
+				}
+				if !handle([]PodEvent{{Type: string(event.Type), Pod: newPodSummary(pod)}}) {
+					return nil
+				}
+			case watch.Error:
+				return fmt.Errorf("unable to watch pods: %w", apierrors.FromObject(event.Object))
+			}
+		}
+	}
+}
+
+// DeletePod deletes a pod in the specified namespace.
+// It returns an error if the pod cannot be deleted.
+func (c *Client) DeletePod(ctx context.Context, namespace, podName string) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	err := c.client.CoreV1().Pods(namespace).Delete(ctx, podName, metav1.DeleteOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to delete pod %s in namespace %s: %w", podName, namespace, err)
+	}
+
+	return nil
+}
+
+// newPodSummary converts a Kubernetes pod into a PodSummary. Like kubectl, the ready containers include the sidecars,
+// the init containers that keep running next to the containers.
+func newPodSummary(pod *corev1.Pod) PodSummary {
+	total := len(pod.Spec.Containers)
+	ready := 0
+	var restarts int32
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Ready {
+			ready++
+		}
+		restarts += status.RestartCount
+	}
+	sidecars := sidecarNames(pod)
+	for _, status := range pod.Status.InitContainerStatuses {
+		restarts += status.RestartCount
+		if sidecars[status.Name] {
+			total++
+			if status.Ready {
+				ready++
+			}
+		}
+	}
+	containers := make([]string, 0, len(pod.Spec.Containers))
+	for _, container := range pod.Spec.Containers {
+		containers = append(containers, container.Name)
+	}
+
+	return PodSummary{
+		Name:       pod.Name,
+		Namespace:  pod.Namespace,
+		Ready:      fmt.Sprintf("%d/%d", ready, total),
+		Status:     podStatus(pod),
+		Restarts:   restarts,
+		Node:       pod.Spec.NodeName,
+		Containers: containers,
+		Created:    pod.CreationTimestamp.Time,
+	}
+}
+
+// podStatus returns the status of a pod in the same way kubectl reports it (e.g. CrashLoopBackOff, Terminating).
+func podStatus(pod *corev1.Pod) string {
+	if pod.DeletionTimestamp != nil {
+		return "Terminating"
+	}
+	status := string(pod.Status.Phase)
+	if pod.Status.Reason != "" {
+		status = pod.Status.Reason
+	}
+	sidecars := sidecarNames(pod)
+	for _, cs := range pod.Status.InitContainerStatuses {
+		switch {
+		case cs.State.Terminated != nil && cs.State.Terminated.ExitCode == 0:
+			continue
+		case sidecars[cs.Name] && cs.Started != nil && *cs.Started:
+			continue
+		case cs.State.Terminated != nil:
+			return "Init:" + valueOr(cs.State.Terminated.Reason, "Error")
+		case cs.State.Waiting != nil && cs.State.Waiting.Reason != "" && cs.State.Waiting.Reason != "PodInitializing":
+			return "Init:" + cs.State.Waiting.Reason
+		default:
+			return "Init"
+		}
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
+			status = cs.State.Waiting.Reason
+		} else if cs.State.Terminated != nil && cs.State.Terminated.Reason != "" {
+			status = cs.State.Terminated.Reason
+		}
+	}
+
+	return status
+}
+
+// sidecarNames returns the names of the sidecars of a pod, the init containers with restart policy Always.
+func sidecarNames(pod *corev1.Pod) map[string]bool {
+	sidecars := map[string]bool{}
+	for _, container := range pod.Spec.InitContainers {
+		if container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			sidecars[container.Name] = true
+		}
+	}
+
+	return sidecars
+}
+
+// containerState returns a short description of the state of a container.
+func containerState(state corev1.ContainerState) string {
+	switch {
+	case state.Running != nil:
+		return "Running"
+	case state.Waiting != nil:
+		return "Waiting: " + valueOr(state.Waiting.Reason, "unknown")
+	case state.Terminated != nil:
+		return fmt.Sprintf("Terminated: %s (exit code %d)", valueOr(state.Terminated.Reason, "unknown"), state.Terminated.ExitCode)
+	}
+
+	return "Unknown"
+}
+
+// valueOr returns s, or fallback if s is empty.
+func valueOr(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+
+	return s
+}
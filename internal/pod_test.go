@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestNewPodSummary(t *testing.T) {
+	running := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	tests := []struct {
+		name         string
+		sidecar      bool
+		init         corev1.ContainerStatus
+		wantReady    string
+		wantStatus   string
+		wantRestarts int32
+	}{
+		{
+			name:         "running sidecar",
+			sidecar:      true,
+			init:         corev1.ContainerStatus{Name: "proxy", State: running, Started: ptr.To(true), Ready: true, RestartCount: 1},
+			wantReady:    "2/2",
+			wantStatus:   "Running",
+			wantRestarts: 3,
+		},
+		{
+			name:         "sidecar that has not started",
+			sidecar:      true,
+			init:         corev1.ContainerStatus{Name: "proxy", State: running, Started: ptr.To(false)},
+			wantReady:    "1/2",
+			wantStatus:   "Init",
+			wantRestarts: 2,
+		},
+		{
+			name:         "running init container",
+			init:         corev1.ContainerStatus{Name: "migrate", State: running, Started: ptr.To(true)},
+			wantReady:    "1/1",
+			wantStatus:   "Init",
+			wantRestarts: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := pod("payments", "api-1", "api")
+			init := corev1.Container{Name: tt.init.Name}
+			if tt.sidecar {
+				init.RestartPolicy = ptr.To(corev1.ContainerRestartPolicyAlways)
+			}
+			p.Spec.InitContainers = []corev1.Container{init}
+			p.Status.Phase = corev1.PodRunning
+			p.Status.InitContainerStatuses = []corev1.ContainerStatus{tt.init}
+			p.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "api", State: running, Ready: true, RestartCount: 2}}
+
+			got := newPodSummary(p)
+			if got.Ready != tt.wantReady || got.Status != tt.wantStatus || got.Restarts != tt.wantRestarts {
+				t.Errorf("newPodSummary() = %s %s %d restarts, want %s %s %d restarts", got.Ready, got.Status, got.Restarts,
+					tt.wantReady, tt.wantStatus, tt.wantRestarts)
+			}
+		})
+	}
+}
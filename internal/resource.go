@@ -0,0 +1,364 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/kubectl/pkg/describe"
+)
+
+// tableAcceptHeader asks the API server for the server-side table of a resource, the columns kubectl prints, and
+// falls back to the plain objects for servers that cannot print tables.
+const tableAcceptHeader = "application/json;as=Table;v=v1;g=meta.k8s.io,application/json"
+
+// errNoDynamicClient is returned by the methods working on any resource type if the client has no dynamic client.
+var errNoDynamicClient = errors.New("no dynamic client")
+
+// APIResource is a resource type served by the API server, e.g. deployments in the apps group.
+type APIResource struct {
+	Group      string
+	Version    string
+	Resource   string
+	Kind       string
+	Namespaced bool
+}
+
+// String returns the resource the way kubectl prints it, e.g. "deployments.apps", or "pods" for the core group.
+func (r APIResource) String() string {
+	if r.Group == "" {
+		return r.Resource
+	}
+
+	return r.Resource + "." + r.Group
+}
+
+// ObjectName returns the name of an object of the resource the way kubectl get -o name prints it, e.g.
+// "deployment.apps/api".
+func (r APIResource) ObjectName(name string) string {
+	kind := strings.ToLower(r.Kind)
+	if r.Group != "" {
+		kind += "." + r.Group
+	}
+
+	return kind + "/" + name
+}
+
+func (r APIResource) groupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: r.Group, Version: r.Version, Resource: r.Resource}
+}
+
+// Table is the server-side table of a resource, with the columns kubectl prints for it.
+type Table struct {
+	Columns []TableColumn
+	Rows    []TableRow
+}
+
+// TableColumn is a column of a table. Columns with a priority above 0 are only shown in the wide output.
+type TableColumn struct {
+	Name     string
+	Priority int32
+}
+
+// TableRow is a row of a table, with the namespace and name of the object it shows.
+type TableRow struct {
+	Namespace string
+	Name      string
+	Cells     []string
+}
+
+// restMapper returns a mapper that resolves resources, kinds and short names using the cached discovery data.
+func (c *Client) restMapper() meta.RESTMapper {
+	return restmapper.NewShortcutExpander(restmapper.NewDeferredDiscoveryRESTMapper(c.discovery), c.discovery, nil)
+}
+
+// withTimeout runs a call that takes no context, like discovery and the kubectl describers, and returns when the call
+// is done, the request timeout of the client has passed or ctx is cancelled. A call that is given up keeps running in
+// the background until its requests end.
+func withTimeout[T any](ctx context.Context, c *Client, call func() (T, error)) (T, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	type result struct {
+		value T
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		value, err := call()
+		done <- result{value, err}
+	}()
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// FindResource resolves a resource type the way kubectl get takes it, e.g. "po", "deployment", "Deployment" or
+// "deploy.apps", using the cached discovery data, so it works for built-in resources and custom resources alike.
+// It returns an error if the server does not have the resource type.
+func (c *Client) FindResource(ctx context.Context, resource string) (APIResource, error) {
+	return withTimeout(ctx, c, func() (APIResource, error) {
+		return c.findResource(resource)
+	})
+}
+
+// findResource resolves a resource type for FindResource.
+func (c *Client) findResource(resource string) (APIResource, error) {
+	gvr, gr := schema.ParseResourceArg(resource)
+	partial := gr.WithVersion("")
+	if gvr != nil {
+		partial = *gvr
+	}
+	mapper := c.restMapper()
+	resolved, err := mapper.ResourceFor(partial)
+	if err != nil {
+		return APIResource{}, fmt.Errorf("the server doesn't have a resource type %s: %w", resource, err)
+	}
+	kind, err := mapper.KindFor(resolved)
+	if err != nil {
+		return APIResource{}, fmt.Errorf("unable to find the kind of %s: %w", resolved.GroupResource(), err)
+	}
+	mapping, err := mapper.RESTMapping(kind.GroupKind(), kind.Version)
+	if err != nil {
+		return APIResource{}, fmt.Errorf("unable to find the kind of %s: %w", resolved.GroupResource(), err)
+	}
+
+	return APIResource{
+		Group:      resolved.Group,
+		Version:    resolved.Version,
+		Resource:   resolved.Resource,
+		Kind:       kind.Kind,
+		Namespaced: mapping.Scope.Name() == meta.RESTScopeNameNamespace,
+	}, nil
+}
+
+// ListResourceTypes lists the resource types of the server that can be listed, in their preferred version, e.g.
+// for a picker. The resource types of API groups that cannot be discovered are left out.
+// It returns an error if no resource types can be discovered.
+func (c *Client) ListResourceTypes(ctx context.Context) ([]APIResource, error) {
+	lists, err := withTimeout(ctx, c, c.discovery.ServerPreferredResources)
+	if err != nil && len(lists) == 0 {
+		return nil, fmt.Errorf("unable to discover the resource types: %w", err)
+	}
+	var resources []APIResource
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, resource := range list.APIResources {
+			if strings.Contains(resource.Name, "/") || !slices.Contains(resource.Verbs, "list") {
+				continue
+			}
+			resources = append(resources, APIResource{
+				Group:      gv.Group,
+				Version:    gv.Version,
+				Resource:   resource.Name,
+				Kind:       resource.Kind,
+				Namespaced: resource.Namespaced,
+			})
+		}
+	}
+	slices.SortFunc(resources, func(a, b APIResource) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	return resources, nil
+}
+
+// resourceInterface returns the dynamic client of the resource type in the namespace. Cluster-scoped resources
+// ignore the namespace, and an empty namespace selects all namespaces.
+func (c *Client) resourceInterface(resource APIResource, namespace string) (dynamic.ResourceInterface, error) {
+	if c.dynamic == nil {
+		return nil, errNoDynamicClient
+	}
+	if !resource.Namespaced {
+		return c.dynamic.Resource(resource.groupVersionResource()), nil
+	}
+
+	return c.dynamic.Resource(resource.groupVersionResource()).Namespace(namespace), nil
+}
+
+// GetObjects retrieves the object with the name, or else the objects matching the label selector, of a resource type in
+// the namespace. An empty namespace retrieves the objects in all namespaces.
+// It returns an error if the objects cannot be retrieved.
+func (c *Client) GetObjects(ctx context.Context, resource APIResource, namespace, name, selector string) ([]unstructured.Unstructured, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	client, err := c.resourceInterface(resource, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get %s: %w", resource, err)
+	}
+	if name != "" {
+		object, err := client.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("unable to get %s %s: %w", resource, name, err)
+		}
+		return []unstructured.Unstructured{*object}, nil
+	}
+	list, err := client.List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list %s: %w", resource, err)
+	}
+
+	return list.Items, nil
+}
+
+// GetTable retrieves the server-side table of the object with the name, or else of the objects matching the label
+// selector, of a resource type in the namespace, so the columns match kubectl get. An empty namespace retrieves the
+// objects in all namespaces. Servers that cannot print tables get a table with the name and age of the objects.
+// It returns an error if the objects cannot be retrieved.
+func (c *Client) GetTable(ctx context.Context, resource APIResource, namespace, name, selector string) (*Table, error) {
+	restClient := c.client.Discovery().RESTClient()
+	if restClient == nil {
+		return c.defaultTable(ctx, resource, namespace, name, selector)
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	path := "/apis/" + resource.Group + "/" + resource.Version
+	if resource.Group == "" {
+		path = "/api/" + resource.Version
+	}
+	if resource.Namespaced && namespace != "" {
+		path += "/namespaces/" + namespace
+	}
+	path += "/" + resource.Resource
+	if name != "" {
+		path += "/" + name
+	}
+	request := restClient.Get().AbsPath(path).SetHeader("Accept", tableAcceptHeader).Param("includeObject", "Metadata")
+	if selector != "" && name == "" {
+		request = request.Param("labelSelector", selector)
+	}
+	body, err := request.DoRaw(ctx)
+	if err != nil {
+		if name != "" {
+			return nil, fmt.Errorf("unable to get %s %s: %w", resource, name, err)
+		}
+		return nil, fmt.Errorf("unable to list %s: %w", resource, err)
+	}
+	var table metav1.Table
+	if err := json.Unmarshal(body, &table); err != nil || table.Kind != "Table" {
+		return c.defaultTable(ctx, resource, namespace, name, selector)
+	}
+
+	return newTable(&table), nil
+}
+
+// newTable converts a server-side table, formatting its cells the way kubectl does.
+func newTable(table *metav1.Table) *Table {
+	t := &Table{Columns: make([]TableColumn, 0, len(table.ColumnDefinitions))}
+	for _, column := range table.ColumnDefinitions {
+		t.Columns = append(t.Columns, TableColumn{Name: strings.ToUpper(column.Name), Priority: column.Priority})
+	}
+	for _, row := range table.Rows {
+		r := TableRow{Cells: make([]string, 0, len(row.Cells))}
+		var object metav1.PartialObjectMetadata
+		if err := json.Unmarshal(row.Object.Raw, &object); err == nil {
+			r.Namespace = object.Namespace
+			r.Name = object.Name
+		}
+		for _, cell := range row.Cells {
+			if cell == nil {
+				r.Cells = append(r.Cells, "<none>")
+				continue
+			}
+			r.Cells = append(r.Cells, fmt.Sprint(cell))
+		}
+		t.Rows = append(t.Rows, r)
+	}
+
+	return t
+}
+
+// defaultTable returns a table with the name and age of the objects, like kubectl prints resources without columns.
+func (c *Client) defaultTable(ctx context.Context, resource APIResource, namespace, name, selector string) (*Table, error) {
+	objects, err := c.GetObjects(ctx, resource, namespace, name, selector)
+	if err != nil {
+		return nil, err
+	}
+	table := &Table{Columns: []TableColumn{{Name: "NAME"}, {Name: "AGE"}}}
+	for _, object := range objects {
+		age := "<unknown>"
+		if created := object.GetCreationTimestamp(); !created.IsZero() {
+			age = duration.HumanDuration(time.Since(created.Time))
+		}
+		table.Rows = append(table.Rows, TableRow{
+			Namespace: object.GetNamespace(),
+			Name:      object.GetName(),
+			Cells:     []string{object.GetName(), age},
+		})
+	}
+
+	return table, nil
+}
+
+// DescribeObject describes an object of a resource type in the namespace the way kubectl describe does, including
+// its events. Resource types without a dedicated describer show their fields.
+// It returns an error if the object cannot be described.
+func (c *Client) DescribeObject(ctx context.Context, resource APIResource, namespace, name string) (string, error) {
+	if c.config == nil {
+		return "", fmt.Errorf("unable to describe %s %s: the client has no REST config", resource, name)
+	}
+	if !resource.Namespaced {
+		namespace = ""
+	}
+	// The describers take no context, so their requests are bounded by the request timeout of a copy of the config.
+	config := rest.CopyConfig(c.config)
+	config.Timeout = c.timeout
+	output, err := withTimeout(ctx, c, func() (string, error) {
+		mapping, err := c.restMapper().RESTMapping(schema.GroupKind{Group: resource.Group, Kind: resource.Kind}, resource.Version)
+		if err != nil {
+			return "", err
+		}
+		describer, exists := describe.DescriberFor(mapping.GroupVersionKind.GroupKind(), config)
+		if !exists {
+			describer, exists = describe.GenericDescriberFor(mapping, config)
+			if !exists {
+				return "", errors.New("no describer")
+			}
+		}
+		return describer.Describe(namespace, name, describe.DescriberSettings{ShowEvents: true, ChunkSize: 500})
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to describe %s %s: %w", resource, name, err)
+	}
+
+	return output, nil
+}
+
+// DeleteObject deletes an object of a resource type in the namespace. Objects it owns are deleted in the
+// background by the garbage collector.
+// It returns an error if the object cannot be deleted.
+func (c *Client) DeleteObject(ctx context.Context, resource APIResource, namespace, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	client, err := c.resourceInterface(resource, namespace)
+	if err != nil {
+		return fmt.Errorf("unable to delete %s %s: %w", resource, name, err)
+	}
+	if err := client.Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+		return fmt.Errorf("unable to delete %s %s: %w", resource, name, err)
+	}
+
+	return nil
+}
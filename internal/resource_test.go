@@ -0,0 +1,113 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// tableServer is an API server that serves the discovery data of pods and the server-side table of the pods in the
+// default namespace.
+func tableServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api":
+			fmt.Fprint(w, `{"kind":"APIVersions","versions":["v1"]}`)
+		case "/apis":
+			fmt.Fprint(w, `{"kind":"APIGroupList","groups":[]}`)
+		case "/api/v1":
+			fmt.Fprint(w, `{"kind":"APIResourceList","groupVersion":"v1","resources":[
+				{"name":"pods","singularName":"pod","kind":"Pod","namespaced":true,"shortNames":["po"],"verbs":["get","list"]}]}`)
+		case "/api/v1/namespaces/default/pods":
+			if !strings.Contains(r.Header.Get("Accept"), "as=Table") || r.URL.Query().Get("labelSelector") != "app=api" {
+				http.Error(w, "unexpected request", http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{"kind":"Table","apiVersion":"meta.k8s.io/v1",
+				"columnDefinitions":[{"name":"Name"},{"name":"Ready"},{"name":"IP","priority":1},{"name":"Nominated Node","priority":1}],
+				"rows":[{"cells":["api-1","1/1","10.0.0.5",null],"object":{"kind":"PartialObjectMetadata","metadata":{"name":"api-1","namespace":"default"}}}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestGetTable(t *testing.T) {
+	server := tableServer(t)
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	config := fmt.Sprintf(`apiVersion: v1
+kind: Config
+current-context: test
+clusters:
+- {name: test, cluster: {server: %q}}
+contexts:
+- {name: test, context: {cluster: test, user: test}}
+users:
+- {name: test, user: {token: test}}
+`, server.URL)
+	if err := os.WriteFile(kubeconfig, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewClient(kubeconfig, "test", time.Second, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource, err := c.FindResource(t.Context(), "po")
+	if err != nil {
+		t.Fatalf("FindResource(po) error = %v", err)
+	}
+	if want := (APIResource{Version: "v1", Resource: "pods", Kind: "Pod", Namespaced: true}); resource != want {
+		t.Errorf("FindResource(po) = %+v, want %+v", resource, want)
+	}
+
+	table, err := c.GetTable(t.Context(), resource, "default", "", "app=api")
+	if err != nil {
+		t.Fatalf("GetTable() error = %v", err)
+	}
+	want := &Table{
+		Columns: []TableColumn{{Name: "NAME"}, {Name: "READY"}, {Name: "IP", Priority: 1}, {Name: "NOMINATED NODE", Priority: 1}},
+		Rows:    []TableRow{{Namespace: "default", Name: "api-1", Cells: []string{"api-1", "1/1", "10.0.0.5", "<none>"}}},
+	}
+	if !reflect.DeepEqual(table, want) {
+		t.Errorf("GetTable() = %+v, want %+v", table, want)
+	}
+
+	if _, err := c.GetTable(t.Context(), resource, "default", "db-1", ""); err == nil {
+		t.Errorf("GetTable(db-1) error = nil, want an error for the missing pod")
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	c := NewClientFromInterface(fake.NewClientset(), 10*time.Millisecond)
+	release := make(chan struct{})
+	defer close(release)
+
+	// A call that hangs, like a describer on an unreachable cluster, is given up after the request timeout.
+	_, err := withTimeout(t.Context(), c, func() (string, error) {
+		<-release
+		return "late", nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("withTimeout() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	got, err := withTimeout(t.Context(), c, func() (string, error) { return "done", nil })
+	if err != nil || got != "done" {
+		t.Errorf("withTimeout() = %q, %v, want done", got, err)
+	}
+}
@@ -0,0 +1,106 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type Secret struct {
+	Name      string
+	Namespace string
+	Type      string
+	Keys      int
+	Created   time.Time
+}
+
+// GetSecrets retrieves the list of secrets in the specified namespace.
+// It returns a slice of secrets and an error if the secrets cannot be retrieved.
+func (c *Client) GetSecrets(ctx context.Context, namespace string) ([]Secret, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	secrets, err := c.client.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get secrets: %w", err)
+	}
+	out := make([]Secret, 0, len(secrets.Items))
+	for _, secret := range secrets.Items {
+		out = append(out, Secret{
+			Name:      secret.Name,
+			Namespace: secret.Namespace,
+			Type:      string(secret.Type),
+			Keys:      len(secret.Data),
+			Created:   secret.CreationTimestamp.Time,
+		})
+	}
+
+	return out, nil
+}
+
+// GetSecretKeys retrieves the keys of a secret together with the size of their decoded values.
+// It returns the keys sorted by name and an error if the secret cannot be retrieved.
+func (c *Client) GetSecretKeys(ctx context.Context, namespace, secretName string) ([]DataKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	secret, err := c.client.CoreV1().Secrets(namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get secret %s in namespace %s: %w", secretName, namespace, err)
+	}
+	out := make([]DataKey, 0, len(secret.Data))
+	for key, value := range secret.Data {
+		out = append(out, DataKey{Name: key, Size: len(value)})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+
+	return out, nil
+}
+
+// GetSecretValue retrieves the base64 decoded value of a single key of a secret, and the resourceVersion of the
+// secret to pass to SetSecretValue.
+// It returns an error if the secret cannot be retrieved or if the key does not exist.
+func (c *Client) GetSecretValue(ctx context.Context, namespace, secretName, key string) ([]byte, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	secret, err := c.client.CoreV1().Secrets(namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to get secret %s in namespace %s: %w", secretName, namespace, err)
+	}
+	value, exists := secret.Data[key]
+	if !exists {
+		return nil, "", fmt.Errorf("key '%s' does not exist in secret %s", key, secretName)
+	}
+
+	return value, secret.ResourceVersion, nil
+}
+
+// SetSecretValue updates the value of a single key of a secret, if the secret still has the resourceVersion it was
+// read with. The value is base64 encoded by the JSON encoding of the byte slice.
+// It returns an error if the secret cannot be patched, e.g. because it was changed in the meantime.
+func (c *Client) SetSecretValue(ctx context.Context, namespace, secretName, key string, value []byte, resourceVersion string) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]string{"resourceVersion": resourceVersion},
+		"data":     map[string][]byte{key: value},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create patch: %w", err)
+	}
+	_, err = c.client.CoreV1().Secrets(namespace).Patch(ctx, secretName, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to update secret %s in namespace %s: %w", secretName, namespace, err)
+	}
+
+	return nil
+}
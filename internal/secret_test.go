@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestSetSecretValue(t *testing.T) {
+	clientset := fake.NewClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "payments", Name: "db", ResourceVersion: "7"},
+		Data:       map[string][]byte{"password": []byte("old")},
+	})
+	// The fake clientset ignores the resourceVersion of a patch, so the server is simulated: the secret was changed
+	// after it was read with resourceVersion 6.
+	clientset.PrependReactor("patch", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		var patch struct {
+			Metadata metav1.ObjectMeta `json:"metadata"`
+		}
+		if err := json.Unmarshal(action.(k8stesting.PatchActionImpl).GetPatch(), &patch); err != nil {
+			return true, nil, err
+		}
+		if patch.Metadata.ResourceVersion != "7" {
+			return true, nil, apierrors.NewConflict(schema.GroupResource{Resource: "secrets"}, "db", nil)
+		}
+		return false, nil, nil
+	})
+	c := NewClientFromInterface(clientset, time.Second)
+
+	value, resourceVersion, err := c.GetSecretValue(t.Context(), "payments", "db", "password")
+	if err != nil || string(value) != "old" || resourceVersion != "7" {
+		t.Fatalf("GetSecretValue() = %q, %q, %v, want old with resourceVersion 7", value, resourceVersion, err)
+	}
+	if err := c.SetSecretValue(t.Context(), "payments", "db", "password", []byte("new"), "6"); !IsConflict(err) {
+		t.Errorf("SetSecretValue() with a stale resourceVersion error = %v, want a conflict", err)
+	}
+	if err := c.SetSecretValue(t.Context(), "payments", "db", "password", []byte("new"), resourceVersion); err != nil {
+		t.Fatalf("SetSecretValue() error = %v", err)
+	}
+	if value, _, _ := c.GetSecretValue(t.Context(), "payments", "db", "password"); string(value) != "new" {
+		t.Errorf("GetSecretValue() after SetSecretValue() = %q, want new", value)
+	}
+}
@@ -0,0 +1,170 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type Service struct {
+	Name       string
+	Namespace  string
+	Type       string
+	ClusterIP  string
+	ExternalIP string
+	Ports      string
+	Selector   string
+	Created    time.Time
+}
+
+type Endpoint struct {
+	Address  string
+	Ports    string
+	PodName  string
+	NodeName string
+	Ready    bool
+}
+
+// GetServices retrieves the list of services in the specified namespace.
+// It returns a slice of services and an error if the services cannot be retrieved.
+func (c *Client) GetServices(ctx context.Context, namespace string) ([]Service, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	services, err := c.client.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get services: %w", err)
+	}
+	out := make([]Service, 0, len(services.Items))
+	for _, svc := range services.Items {
+		out = append(out, newService(&svc))
+	}
+
+	return out, nil
+}
+
+// GetService retrieves a single service in the specified namespace.
+// It returns an error if the service cannot be retrieved.
+func (c *Client) GetService(ctx context.Context, namespace, serviceName string) (*Service, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	svc, err := c.client.CoreV1().Services(namespace).Get(ctx, serviceName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get service %s in namespace %s: %w", serviceName, namespace, err)
+	}
+	out := newService(svc)
+
+	return &out, nil
+}
+
+// GetServiceEndpoints retrieves the endpoints of a service by resolving the EndpointSlices that belong to it.
+// It returns the endpoints sorted by address and an error if the EndpointSlices cannot be retrieved.
+func (c *Client) GetServiceEndpoints(ctx context.Context, namespace, serviceName string) ([]Endpoint, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	slices, err := c.client.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + serviceName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get endpoints of service %s in namespace %s: %w", serviceName, namespace, err)
+	}
+	var out []Endpoint
+	for _, slice := range slices.Items {
+		ports := make([]string, 0, len(slice.Ports))
+		for _, port := range slice.Ports {
+			if port.Port == nil {
+				continue
+			}
+			protocol := corev1.ProtocolTCP
+			if port.Protocol != nil {
+				protocol = *port.Protocol
+			}
+			ports = append(ports, fmt.Sprintf("%d/%s", *port.Port, protocol))
+		}
+		for _, ep := range slice.Endpoints {
+			endpoint := Endpoint{
+				Ports: strings.Join(ports, ","),
+				// A nil ready condition must be interpreted as ready.
+				Ready: ep.Conditions.Ready == nil || *ep.Conditions.Ready,
+			}
+			if ep.TargetRef != nil && ep.TargetRef.Kind == "Pod" {
+				endpoint.PodName = ep.TargetRef.Name
+			}
+			if ep.NodeName != nil {
+				endpoint.NodeName = *ep.NodeName
+			}
+			for _, address := range ep.Addresses {
+				endpoint.Address = address
+				out = append(out, endpoint)
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Address < out[j].Address
+	})
+
+	return out, nil
+}
+
+// newService converts a Kubernetes service into a Service.
+func newService(svc *corev1.Service) Service {
+	ports := make([]string, 0, len(svc.Spec.Ports))
+	for _, port := range svc.Spec.Ports {
+		if port.NodePort != 0 {
+			ports = append(ports, fmt.Sprintf("%d:%d/%s", port.Port, port.NodePort, port.Protocol))
+		} else {
+			ports = append(ports, fmt.Sprintf("%d/%s", port.Port, port.Protocol))
+		}
+	}
+	selector := make([]string, 0, len(svc.Spec.Selector))
+	for key, value := range svc.Spec.Selector {
+		selector = append(selector, key+"="+value)
+	}
+	sort.Strings(selector)
+
+	return Service{
+		Name:       svc.Name,
+		Namespace:  svc.Namespace,
+		Type:       string(svc.Spec.Type),
+		ClusterIP:  svc.Spec.ClusterIP,
+		ExternalIP: serviceExternalIP(svc),
+		Ports:      strings.Join(ports, ","),
+		Selector:   strings.Join(selector, ","),
+		Created:    svc.CreationTimestamp.Time,
+	}
+}
+
+// serviceExternalIP returns the external IP of a service in the same way kubectl reports it.
+func serviceExternalIP(svc *corev1.Service) string {
+	switch svc.Spec.Type {
+	case corev1.ServiceTypeExternalName:
+		return svc.Spec.ExternalName
+	case corev1.ServiceTypeLoadBalancer:
+		addresses := make([]string, 0, len(svc.Status.LoadBalancer.Ingress)+len(svc.Spec.ExternalIPs))
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			if ingress.IP != "" {
+				addresses = append(addresses, ingress.IP)
+			} else if ingress.Hostname != "" {
+				addresses = append(addresses, ingress.Hostname)
+			}
+		}
+		addresses = append(addresses, svc.Spec.ExternalIPs...)
+		if len(addresses) == 0 {
+			return "<pending>"
+		}
+		return strings.Join(addresses, ",")
+	}
+	if len(svc.Spec.ExternalIPs) > 0 {
+		return strings.Join(svc.Spec.ExternalIPs, ",")
+	}
+
+	return "<none>"
+}
@@ -0,0 +1,171 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+// ClusterStatus is a summary of the health of a cluster.
+// Errors holds the queries that failed; the corresponding parts of the summary are empty.
+type ClusterStatus struct {
+	Server      string                  `json:"server"`
+	Reachable   bool                    `json:"reachable"`
+	Version     string                  `json:"version,omitempty"`
+	Healthy     bool                    `json:"healthy"`
+	Nodes       NodeCounts              `json:"nodes"`
+	Pods        []UnhealthyPods         `json:"unhealthyPods"`
+	Deployments []UnavailableDeployment `json:"unavailableDeployments"`
+	Warnings    []Event                 `json:"warningEvents"`
+	Errors      []string                `json:"errors,omitempty"`
+}
+
+type NodeCounts struct {
+	Total    int `json:"total"`
+	Ready    int `json:"ready"`
+	NotReady int `json:"notReady"`
+}
+
+// UnhealthyPods groups the pods of a namespace that are not running or completed by the reason they report.
+type UnhealthyPods struct {
+	Namespace string   `json:"namespace"`
+	Reason    string   `json:"reason"`
+	Pods      []string `json:"pods"`
+}
+
+type UnavailableDeployment struct {
+	Namespace   string `json:"namespace"`
+	Name        string `json:"name"`
+	Desired     int32  `json:"desired"`
+	Ready       int32  `json:"ready"`
+	Unavailable int32  `json:"unavailable"`
+}
+
+// GetClusterStatus queries the API server version, the nodes, the pods, the deployments and the Warning events
+// seen within since concurrently, all within the request timeout.
+// A failing query is reported in the Errors of the status; only the version query failing marks the cluster unreachable.
+func (c *Client) GetClusterStatus(ctx context.Context, since time.Duration) *ClusterStatus {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	status := &ClusterStatus{
+		Pods:        []UnhealthyPods{},
+		Deployments: []UnavailableDeployment{},
+		Warnings:    []Event{},
+	}
+	if c.config != nil {
+		status.Server = c.config.Host
+	}
+	var mu sync.Mutex
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		status.Errors = append(status.Errors, err.Error())
+	}
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		body, err := c.client.Discovery().RESTClient().Get().AbsPath("/version").DoRaw(ctx)
+		if err != nil {
+			fail(fmt.Errorf("unable to reach API server: %w", err))
+			return
+		}
+		status.Reachable = true
+		var info version.Info
+		if err := json.Unmarshal(body, &info); err != nil {
+			fail(fmt.Errorf("unable to decode API server version: %w", err))
+			return
+		}
+		status.Version = info.GitVersion
+	})
+	wg.Go(func() {
+		nodes, err := c.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			fail(fmt.Errorf("unable to get nodes: %w", err))
+			return
+		}
+		status.Nodes.Total = len(nodes.Items)
+		for _, node := range nodes.Items {
+			if strings.HasPrefix(nodeStatus(&node), "Ready") {
+				status.Nodes.Ready++
+			}
+		}
+		status.Nodes.NotReady = status.Nodes.Total - status.Nodes.Ready
+	})
+	wg.Go(func() {
+		pods, err := c.client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+		if err != nil {
+			fail(fmt.Errorf("unable to get pods: %w", err))
+			return
+		}
+		groups := make(map[[2]string][]string)
+		for _, pod := range pods.Items {
+			reason := podStatus(&pod)
+			if reason == "Running" || reason == "Completed" || reason == "Succeeded" {
+				continue
+			}
+			key := [2]string{pod.Namespace, reason}
+			groups[key] = append(groups[key], pod.Name)
+		}
+		for key, names := range groups {
+			sort.Strings(names)
+			status.Pods = append(status.Pods, UnhealthyPods{Namespace: key[0], Reason: key[1], Pods: names})
+		}
+		sort.Slice(status.Pods, func(i, j int) bool {
+			if status.Pods[i].Namespace != status.Pods[j].Namespace {
+				return status.Pods[i].Namespace < status.Pods[j].Namespace
+			}
+			return status.Pods[i].Reason < status.Pods[j].Reason
+		})
+	})
+	wg.Go(func() {
+		deployments, err := c.client.AppsV1().Deployments("").List(ctx, metav1.ListOptions{})
+		if err != nil {
+			fail(fmt.Errorf("unable to get deployments: %w", err))
+			return
+		}
+		for _, d := range deployments.Items {
+			if d.Status.UnavailableReplicas == 0 {
+				continue
+			}
+			desired := int32(1)
+			if d.Spec.Replicas != nil {
+				desired = *d.Spec.Replicas
+			}
+			status.Deployments = append(status.Deployments, UnavailableDeployment{
+				Namespace:   d.Namespace,
+				Name:        d.Name,
+				Desired:     desired,
+				Ready:       d.Status.ReadyReplicas,
+				Unavailable: d.Status.UnavailableReplicas,
+			})
+		}
+	})
+	wg.Go(func() {
+		events, err := c.client.CoreV1().Events("").List(ctx, metav1.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector("type", "Warning").String(),
+		})
+		if err != nil {
+			fail(fmt.Errorf("unable to get events: %w", err))
+			return
+		}
+		status.Warnings = slices.DeleteFunc(newEvents(events.Items), func(e Event) bool {
+			return time.Since(e.LastSeen) > since
+		})
+	})
+	wg.Wait()
+	sort.Strings(status.Errors)
+
+	status.Healthy = len(status.Errors) == 0 && status.Nodes.NotReady == 0 && len(status.Pods) == 0 && len(status.Deployments) == 0
+
+	return status
+}